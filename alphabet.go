@@ -0,0 +1,88 @@
+package runewidth
+
+// Alphabet is a small perfect hash table with the widths of a fixed set of
+// characters, for fast lookups in hot loops where the possible characters are
+// known in advance, such as when parsing a specific log format.
+//
+// An Alphabet is safe for concurrent use.
+type Alphabet struct {
+	c     *Condition
+	mul   uint32
+	shift uint32
+	keys  []rune
+	width []uint8
+}
+
+// NewAlphabet creates a new Alphabet for all characters in chars, with the
+// widths from c. Widths are copied when the Alphabet is created, so later
+// changes to c are not reflected for characters in the alphabet.
+func (c *Condition) NewAlphabet(chars string) *Alphabet {
+	uniq := make(map[rune]struct{}, len(chars))
+	for _, r := range chars {
+		uniq[r] = struct{}{}
+	}
+	runes := make([]rune, 0, len(uniq))
+	for r := range uniq {
+		runes = append(runes, r)
+	}
+
+	bits := uint32(0)
+	for 1<<bits < len(runes) {
+		bits++
+	}
+	for ; ; bits++ {
+		a := &Alphabet{c: c, shift: 32 - bits}
+		// Try some multipliers from a fixed xorshift sequence; grow the table
+		// if none of them produce a hash without collisions.
+		x := uint32(2463534242)
+		for try := 0; try < 1<<12; try++ {
+			x ^= x << 13
+			x ^= x >> 17
+			x ^= x << 5
+			a.mul = x | 1
+			if a.fill(c, runes) {
+				return a
+			}
+		}
+	}
+}
+
+func (a *Alphabet) fill(c *Condition, runes []rune) bool {
+	size := 1 << (32 - a.shift)
+	a.keys = make([]rune, size)
+	a.width = make([]uint8, size)
+	for i := range a.keys {
+		a.keys[i] = -1
+	}
+	for _, r := range runes {
+		h := a.hash(r)
+		if a.keys[h] != -1 {
+			return false
+		}
+		a.keys[h], a.width[h] = r, uint8(c.RuneWidth(r))
+	}
+	return true
+}
+
+func (a *Alphabet) hash(r rune) uint32 {
+	return uint32(r) * a.mul >> a.shift
+}
+
+// Contains reports if r is in the alphabet.
+func (a *Alphabet) Contains(r rune) bool {
+	return a.keys[a.hash(r)] == r
+}
+
+// RuneWidth returns the number of cells in r. Characters not in the alphabet
+// are looked up in the Condition the Alphabet was created with.
+func (a *Alphabet) RuneWidth(r rune) int {
+	if h := a.hash(r); a.keys[h] == r {
+		return int(a.width[h])
+	}
+	return a.c.RuneWidth(r)
+}
+
+// NewAlphabet creates a new Alphabet for all characters in chars.
+func NewAlphabet(chars string) *Alphabet {
+	return DefaultCondition.NewAlphabet(chars)
+}
@@ -0,0 +1,34 @@
+package runewidth
+
+import "testing"
+
+func TestAlphabet(t *testing.T) {
+	tests := []string{
+		"",
+		"a",
+		"0123456789abcdef",
+		"abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789 -:.[]",
+		"日付時刻エラー警告情報́★",
+	}
+
+	c := newCond(false)
+	for _, chars := range tests {
+		a := c.NewAlphabet(chars)
+		if len(a.keys) > 2*len(chars)+1 {
+			t.Errorf("%q: table too large: %d", chars, len(a.keys))
+		}
+		for _, r := range chars {
+			if !a.Contains(r) {
+				t.Errorf("%q: doesn't contain %q", chars, r)
+			}
+			if have, want := a.RuneWidth(r), c.RuneWidth(r); have != want {
+				t.Errorf("%q: RuneWidth(%q) = %d, want %d", chars, r, have, want)
+			}
+		}
+		for _, r := range "xyz世\x00" {
+			if have, want := a.RuneWidth(r), c.RuneWidth(r); have != want {
+				t.Errorf("%q: RuneWidth(%q) = %d, want %d", chars, r, have, want)
+			}
+		}
+	}
+}
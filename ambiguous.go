@@ -0,0 +1,116 @@
+package runewidth
+
+// Ambiguous characters that are wide in all CJK locales: the characters that
+// are in all of the legacy East Asian encodings (JIS X 0208, KS X 1001,
+// GB 2312, and Big5), where they were displayed as double-width.
+var ambiguousCJK = table{
+	{0x00A7, 0x00A8}, {0x00B0, 0x00B1}, {0x00B4, 0x00B4},
+	{0x00B6, 0x00B6}, {0x00D7, 0x00D7}, {0x00F7, 0x00F7},
+	{0x0391, 0x03A1}, {0x03A3, 0x03A9}, {0x03B1, 0x03C1},
+	{0x03C3, 0x03C9}, {0x2010, 0x2010}, {0x2013, 0x2016},
+	{0x2018, 0x2019}, {0x201C, 0x201D}, {0x2020, 0x2021},
+	{0x2025, 0x2026}, {0x2030, 0x2030}, {0x2032, 0x2033},
+	{0x203B, 0x203B}, {0x2103, 0x2103}, {0x2116, 0x2116},
+	{0x2160, 0x216B}, {0x2170, 0x2179}, {0x2190, 0x2199},
+	{0x21D2, 0x21D2}, {0x21D4, 0x21D4}, {0x2200, 0x2200},
+	{0x2202, 0x2203}, {0x2207, 0x2208}, {0x220B, 0x220B},
+	{0x2211, 0x2211}, {0x221A, 0x221A}, {0x221D, 0x2220},
+	{0x2225, 0x2225}, {0x2227, 0x222C}, {0x222E, 0x222E},
+	{0x2234, 0x2235}, {0x223D, 0x223D}, {0x2252, 0x2252},
+	{0x2260, 0x2261}, {0x2266, 0x2267}, {0x226A, 0x226B},
+	{0x2282, 0x2283}, {0x2286, 0x2287}, {0x22A5, 0x22A5},
+	{0x2312, 0x2312}, {0x2460, 0x2473}, {0x2500, 0x254B},
+	{0x25A0, 0x25A1}, {0x25B2, 0x25B3}, {0x25BC, 0x25BD},
+	{0x25C6, 0x25C7}, {0x25CB, 0x25CB}, {0x25CE, 0x25CF},
+	{0x25EF, 0x25EF}, {0x2605, 0x2606}, {0x2640, 0x2640},
+	{0x2642, 0x2642}, {0x266A, 0x266A}, {0x266D, 0x266D},
+	{0x266F, 0x266F},
+}
+
+// Ambiguous characters that are wide in a specific locale, in addition to
+// ambiguousCJK.
+var ambiguousLocales = map[string]table{
+	// JIS X 0208.
+	"ja": {
+		{0x0401, 0x0401}, {0x0410, 0x044F}, {0x0451, 0x0451},
+	},
+	// KS X 1001.
+	"ko": {
+		{0x00A1, 0x00A1}, {0x00A4, 0x00A4}, {0x00AA, 0x00AA},
+		{0x00AD, 0x00AE}, {0x00B2, 0x00B3}, {0x00B7, 0x00BA},
+		{0x00BC, 0x00BF}, {0x00C6, 0x00C6}, {0x00D0, 0x00D0},
+		{0x00D8, 0x00D8}, {0x00DE, 0x00DF}, {0x00E6, 0x00E6},
+		{0x00F0, 0x00F0}, {0x00F8, 0x00F8}, {0x00FE, 0x00FE},
+		{0x0111, 0x0111}, {0x0126, 0x0127}, {0x0131, 0x0133},
+		{0x0138, 0x0138}, {0x013F, 0x0142}, {0x0149, 0x014B},
+		{0x0152, 0x0153}, {0x0166, 0x0167}, {0x0401, 0x0401},
+		{0x0410, 0x044F}, {0x0451, 0x0451}, {0x2474, 0x2482},
+		{0x249C, 0x24B5}, {0x24D0, 0x24E9}, {0x2592, 0x2592},
+		{0x25A3, 0x25A9}, {0x25B6, 0x25B7}, {0x25C0, 0x25C1},
+		{0x25C8, 0x25C8}, {0x25D0, 0x25D1}, {0x2609, 0x2609},
+		{0x260E, 0x260F}, {0x261C, 0x261C}, {0x261E, 0x261E},
+		{0x2660, 0x2661}, {0x2663, 0x2665}, {0x2667, 0x2669},
+		{0x266C, 0x266C},
+	},
+	// GB 2312.
+	"zh-Hans": {
+		{0x00E0, 0x00E1}, {0x00E8, 0x00EA}, {0x00EC, 0x00ED},
+		{0x00F2, 0x00F3}, {0x00F9, 0x00FA}, {0x00FC, 0x00FC},
+		{0x0101, 0x0101}, {0x0113, 0x0113}, {0x011B, 0x011B},
+		{0x012B, 0x012B}, {0x0144, 0x0144}, {0x0148, 0x0148},
+		{0x014D, 0x014D}, {0x016B, 0x016B}, {0x01CE, 0x01CE},
+		{0x01D0, 0x01D0}, {0x01D2, 0x01D2}, {0x01D4, 0x01D4},
+		{0x01D6, 0x01D6}, {0x01D8, 0x01D8}, {0x01DA, 0x01DA},
+		{0x01DC, 0x01DC}, {0x0251, 0x0251}, {0x0261, 0x0261},
+		{0x02C7, 0x02C7}, {0x02C9, 0x02CB}, {0x02D9, 0x02D9},
+		{0x0401, 0x0401}, {0x0410, 0x044F}, {0x0451, 0x0451},
+		{0x2474, 0x249B}, {0x2550, 0x2573}, {0x2581, 0x258F},
+		{0x2593, 0x2595},
+	},
+	// Big5.
+	"zh-Hant": {
+		{0x02C7, 0x02C7}, {0x02C9, 0x02CB}, {0x02D9, 0x02D9},
+		{0x2295, 0x2295}, {0x2299, 0x2299}, {0x22BF, 0x22BF},
+		{0x2550, 0x2573}, {0x2581, 0x258F}, {0x2593, 0x2595},
+		{0x25E2, 0x25E5}, {0x2609, 0x2609},
+	},
+}
+
+// AmbiguousLocales returns the locales that can be used for
+// Condition.AmbiguousLocale.
+func AmbiguousLocales() []string {
+	return []string{"ja", "ko", "zh-Hans", "zh-Hant"}
+}
+
+// ambiguousWidth returns the width of ambiguous characters, before
+// AmbiguousLocale is applied.
+func (c *Condition) ambiguousWidth() int {
+	if c.AmbiguousWidth == 1 || c.AmbiguousWidth == 2 {
+		return c.AmbiguousWidth
+	}
+	if c.EastAsianWidth {
+		return 2
+	}
+	return 1
+}
+
+// ambiguousWide reports if the ambiguous character r is wide with the
+// AmbiguousLocale.
+func (c *Condition) ambiguousWide(r rune) bool {
+	t, ok := ambiguousLocales[c.AmbiguousLocale]
+	return !ok || inTables(r, ambiguousCJK, t)
+}
+
+// ambiguousLayers returns the LUT layers for AmbiguousLocale, or nil if all
+// ambiguous characters are wide.
+func (c *Condition) ambiguousLayers() []lutLayer {
+	t, ok := ambiguousLocales[c.AmbiguousLocale]
+	if !ok {
+		return nil
+	}
+	return []lutLayer{
+		{[]table{ambiguous}, 1},
+		{[]table{ambiguousCJK, t}, 2},
+		{[]table{c.doublewidth()}, 2},
+	}
+}
@@ -0,0 +1,143 @@
+package runewidth
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestAmbiguousLocaleTables(t *testing.T) {
+	tables := map[string]table{"": ambiguousCJK}
+	for l, tbl := range ambiguousLocales {
+		tables[l] = tbl
+	}
+	for name, tbl := range tables {
+		for i, iv := range tbl {
+			if i > 0 && iv.first <= tbl[i-1].last {
+				t.Errorf("%q: not sorted at %U", name, iv.first)
+			}
+			for r := iv.first; r <= iv.last; r++ {
+				if !inTable(r, ambiguous) {
+					t.Errorf("%q: %U not ambiguous", name, r)
+				}
+				if name != "" && inTable(r, ambiguousCJK) {
+					t.Errorf("%q: %U also in ambiguousCJK", name, r)
+				}
+			}
+		}
+	}
+	if len(AmbiguousLocales()) != len(ambiguousLocales) {
+		t.Errorf("AmbiguousLocales() out of sync")
+	}
+}
+
+func TestAmbiguousLocale(t *testing.T) {
+	tests := []struct {
+		in                   rune
+		none, ja, ko, cn, tw int
+	}{
+		{'☆', 2, 2, 2, 2, 2},
+		{'α', 2, 2, 2, 2, 2},
+		{'Д', 2, 2, 2, 2, 1},
+		{'é', 2, 1, 1, 2, 1},
+		{'ß', 2, 1, 2, 1, 1},
+		{'ⓐ', 2, 1, 2, 1, 1},
+		{'世', 2, 2, 2, 2, 2},
+		{'a', 1, 1, 1, 1, 1},
+	}
+
+	for _, tt := range tests {
+		for i, l := range []string{"", "ja", "ko", "zh-Hans", "zh-Hant"} {
+			want := []int{tt.none, tt.ja, tt.ko, tt.cn, tt.tw}[i]
+			c := newCond(true)
+			c.AmbiguousLocale = l
+			if have := c.RuneWidth(tt.in); have != want {
+				t.Errorf("RuneWidth(%q) with %q = %d, want %d", tt.in, l, have, want)
+			}
+
+			c = newCond(false)
+			c.AmbiguousLocale = l
+			if have := c.RuneWidth(tt.in); have != 1 && tt.in != '世' {
+				t.Errorf("RuneWidth(%q) with %q and EastAsianWidth=false = %d, want 1", tt.in, l, have)
+			}
+		}
+	}
+}
+
+func TestAmbiguousLocaleLUT(t *testing.T) {
+	for _, l := range AmbiguousLocales() {
+		for _, strict := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%s/%t", l, strict), func(t *testing.T) {
+				c := newCond(true)
+				c.AmbiguousLocale, c.StrictEmojiNeutral = l, strict
+				lut := newCond(true)
+				lut.AmbiguousLocale, lut.StrictEmojiNeutral = l, strict
+				lut.CreateLUT()
+
+				for r := rune(0); r < 0x30000; r++ {
+					if have, want := lut.RuneWidth(r), c.RuneWidth(r); have != want {
+						t.Fatalf("%U: LUT has %d, want %d", r, have, want)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestAmbiguousWidth(t *testing.T) {
+	tests := []struct {
+		in     rune
+		ea     bool
+		aw     int
+		locale string
+		want   int
+	}{
+		{'☆', false, 0, "", 1},
+		{'☆', false, 1, "", 1},
+		{'☆', false, 2, "", 2},
+		{'☆', true, 0, "", 2},
+		{'☆', true, 1, "", 1},
+		{'☆', true, 2, "", 2},
+		{'°', false, 2, "", 2},
+		{'é', false, 2, "ja", 1},
+		{'Д', false, 2, "ja", 2},
+		{'\u0300', false, 2, "", 0},
+		{'\u00ad', false, 2, "", 0},
+		{'a', false, 2, "", 1},
+		{'世', true, 1, "", 2},
+
+		// Emoji are still only wide with EastAsianWidth.
+		{'☺', false, 2, "", 1},
+	}
+
+	for _, tt := range tests {
+		c := newCond(tt.ea)
+		c.StrictEmojiNeutral = false
+		c.AmbiguousWidth, c.AmbiguousLocale = tt.aw, tt.locale
+		if have := c.RuneWidth(tt.in); have != tt.want {
+			t.Errorf("RuneWidth(%q) with EastAsianWidth=%t AmbiguousWidth=%d = %d, want %d",
+				tt.in, tt.ea, tt.aw, have, tt.want)
+		}
+	}
+}
+
+func TestAmbiguousWidthLUT(t *testing.T) {
+	for _, ea := range []bool{false, true} {
+		for _, aw := range []int{1, 2} {
+			for _, l := range []string{"", "ja"} {
+				t.Run(fmt.Sprintf("%t/%d/%s", ea, aw, l), func(t *testing.T) {
+					c := newCond(ea)
+					c.AmbiguousWidth, c.AmbiguousLocale = aw, l
+					lut := newCond(ea)
+					lut.AmbiguousWidth, lut.AmbiguousLocale = aw, l
+					lut.CreateLUT()
+
+					for r := rune(0); r < 0x30000; r++ {
+						if have, want := lut.RuneWidth(r), c.RuneWidth(r); have != want {
+							t.Fatalf("%U: LUT has %d, want %d", r, have, want)
+						}
+					}
+				})
+			}
+		}
+	}
+}
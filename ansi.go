@@ -0,0 +1,202 @@
+package runewidth
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ControlPolicy sets how the escape-aware functions such as ClipANSI handle C0
+// control characters in the text, such as BEL, BS, VT, and FF. Tab, line feed,
+// carriage return, and escape characters that start an escape sequence are
+// never affected.
+type ControlPolicy uint8
+
+const (
+	// ControlZero keeps control characters as zero-width characters.
+	ControlZero ControlPolicy = iota
+
+	// ControlIgnore removes control characters, like escape sequences.
+	ControlIgnore
+
+	// ControlReject makes CheckANSI return a *ControlError for control
+	// characters; the other functions treat them like ControlZero.
+	ControlReject
+)
+
+// ControlError is returned by CheckANSI for a control character if the
+// ControlPolicy is ControlReject.
+type ControlError struct {
+	Offset  int  // Byte offset in the string.
+	Control byte // The control character.
+}
+
+func (e *ControlError) Error() string {
+	return fmt.Sprintf("runewidth: control character %#02x at offset %d", e.Control, e.Offset)
+}
+
+// CheckANSI checks s for control characters outside of escape sequences,
+// returning a *ControlError for the first one if ANSIControls is set to
+// ControlReject. It always returns nil for the other policies.
+func (c *Condition) CheckANSI(s string) error {
+	if c.ANSIControls != ControlReject {
+		return nil
+	}
+	if i := indexControl(s); i >= 0 {
+		return &ControlError{Offset: i, Control: s[i]}
+	}
+	return nil
+}
+
+// CheckANSI checks s for control characters outside of escape sequences.
+func CheckANSI(s string) error {
+	return DefaultCondition.CheckANSI(s)
+}
+
+// indexControl returns the offset of the first control character outside of
+// escape sequences that's affected by the ControlPolicy, or -1 if there isn't
+// one.
+func indexControl(s string) int {
+	for i := 0; i < len(s); {
+		if n := escapeLen(s[i:]); n > 0 {
+			i += n
+			continue
+		}
+		if isTextControl(s[i]) {
+			return i
+		}
+		i++
+	}
+	return -1
+}
+
+// isTextControl reports if b is a C0 control character that's affected by
+// the ControlPolicy. This should be called after escapeLen.
+func isTextControl(b byte) bool {
+	return b < 0x20 && b != '\t' && b != '\n' && b != '\r'
+}
+
+// escapeLen returns the length of the escape sequence at the start of s, or 0
+// if s doesn't start with an escape sequence.
+//
+// This recognizes CSI sequences ("\x1b[" … final byte), OSC sequences ("\x1b]"
+// … BEL or ST), DCS sequences such as sixel images ("\x1bP" … ST), APC, PM,
+// and SOS sequences ("\x1b_", "\x1b^", "\x1bX" … ST), and the two-byte escape
+// sequences (optionally with intermediate bytes). An unterminated sequence
+// extends to the end of s.
+func escapeLen(s string) int {
+	if len(s) < 2 || s[0] != 0x1b {
+		return 0
+	}
+	switch s[1] {
+	case '[':
+		for i := 2; i < len(s); i++ {
+			switch b := s[i]; {
+			case b >= 0x40 && b <= 0x7e:
+				return i + 1
+			case b < 0x20 || b > 0x3f:
+				return i // Invalid; stop here.
+			}
+		}
+		return len(s)
+	case ']':
+		return stringSeqLen(s, true)
+	case 'P', '_', '^', 'X':
+		return stringSeqLen(s, false)
+	default:
+		i := 1
+		for i < len(s) && s[i] >= 0x20 && s[i] <= 0x2f {
+			i++
+		}
+		if i < len(s) {
+			return i + 1
+		}
+		return len(s)
+	}
+}
+
+// stringSeqLen returns the length of the control string sequence at the start
+// of s, which is terminated by ST ("\x1b\\") or, if bel is set, BEL.
+func stringSeqLen(s string, bel bool) int {
+	for i := 2; i < len(s); i++ {
+		if bel && s[i] == 0x07 {
+			return i + 1
+		}
+		if s[i] == 0x1b && i+1 < len(s) && s[i+1] == '\\' {
+			return i + 2
+		}
+	}
+	return len(s)
+}
+
+// isImage reports if the escape sequence seq is an inline image: either the
+// kitty graphics protocol ("\x1b_G") or iTerm2 inline images ("\x1b]1337;File=").
+func isImage(seq string) bool {
+	return strings.HasPrefix(seq, "\x1b_G") || strings.HasPrefix(seq, "\x1b]1337;File=")
+}
+
+// ImageCells returns the width in cells declared in the inline image escape
+// sequence seq, for the kitty graphics protocol ("c=" key) and iTerm2 inline
+// images ("width=" key, without a unit). It returns false if seq isn't an
+// image or doesn't declare the width in cells.
+//
+// This can be used as the ImageWidth callback in the Condition.
+func ImageCells(seq string) (int, bool) {
+	var params, sep, key string
+	switch {
+	case strings.HasPrefix(seq, "\x1b_G"):
+		params, sep, key = seq[3:], ",", "c="
+		if i := strings.IndexByte(params, ';'); i > -1 {
+			params = params[:i]
+		}
+	case strings.HasPrefix(seq, "\x1b]1337;File="):
+		params, sep, key = seq[len("\x1b]1337;File="):], ";", "width="
+		if i := strings.IndexByte(params, ':'); i > -1 {
+			params = params[:i]
+		}
+	default:
+		return 0, false
+	}
+	for _, p := range strings.Split(params, sep) {
+		if !strings.HasPrefix(p, key) {
+			continue
+		}
+		n, err := strconv.Atoi(p[len(key):])
+		if err != nil || n < 0 {
+			return 0, false
+		}
+		return n, true
+	}
+	return 0, false
+}
+
+// isSGR reports if the escape sequence seq is a SGR ("Select Graphic
+// Rendition") sequence, which sets colours and other attributes.
+func isSGR(seq string) bool {
+	return len(seq) >= 3 && seq[1] == '[' && seq[len(seq)-1] == 'm'
+}
+
+// sgrState tracks the SGR sequences that are currently active.
+type sgrState []string
+
+const sgrReset = "\x1b[0m"
+
+// hyperlinkEnd closes an OSC 8 hyperlink.
+const hyperlinkEnd = "\x1b]8;;\x1b\\"
+
+// isHyperlink reports if the escape sequence seq is an OSC 8 hyperlink, which
+// is "\x1b]8;params;URI" terminated by ST or BEL. An empty URI closes the link.
+func isHyperlink(seq string) bool {
+	return strings.HasPrefix(seq, "\x1b]8;")
+}
+
+func (st *sgrState) add(seq string) {
+	switch p := seq[2 : len(seq)-1]; {
+	case p == "" || p == "0":
+		*st = (*st)[:0]
+	case strings.HasPrefix(p, "0;"):
+		*st = append((*st)[:0], seq)
+	default:
+		*st = append(*st, seq)
+	}
+}
@@ -0,0 +1,180 @@
+package runewidth
+
+import "strings"
+
+// ansiKind is the kind of unit returned by ansiUnit.
+type ansiKind uint8
+
+const (
+	ansiText    ansiKind = iota // Character, or inline image.
+	ansiEscape                  // Escape sequence.
+	ansiControl                 // Control character that's removed with ControlIgnore.
+)
+
+// ansiUnit returns the length, width, and kind of the escape sequence or
+// character at the start of s, which is at column col. Inline images have the
+// width from ImageWidth.
+func (c *Condition) ansiUnit(s string, col int) (n, w int, kind ansiKind) {
+	if n := escapeLen(s); n > 0 {
+		if c.ImageWidth != nil && isImage(s[:n]) {
+			if w := c.ImageWidth(s[:n]); w > 0 {
+				return n, w, ansiText
+			}
+		}
+		return n, 0, ansiEscape
+	}
+	if c.ANSIControls == ControlIgnore && isTextControl(s[0]) {
+		return 1, 0, ansiControl
+	}
+	n, w = c.clusterAt(s, col)
+	return n, w, ansiText
+}
+
+// StringWidthANSI returns the number of cells in s, ignoring ANSI escape
+// sequences. Control characters are handled according to ANSIControls.
+func (c *Condition) StringWidthANSI(s string) int {
+	return c.widthANSIAt(s, 0)
+}
+
+// widthANSIAt returns the width of s like StringWidthANSI, if s starts at
+// column col.
+func (c *Condition) widthANSIAt(s string, col int) (width int) {
+	line := -col // Width at the start of the current line.
+	for i := 0; i < len(s); {
+		if s[i] == '\n' {
+			line = width
+		}
+		n, w, _ := c.ansiUnit(s[i:], width-line)
+		width += w
+		i += n
+	}
+	return width
+}
+
+// TruncateANSI is like Truncate, but skips ANSI escape sequences. Control
+// characters are handled according to ANSIControls.
+//
+// Escape sequences before the point where s is truncated are kept, and the
+// escape sequences after it are removed. If any SGR attributes (colours, bold,
+// etc.) are active at that point a reset is added after the tail, and an open
+// OSC 8 hyperlink is closed, so that they don't leak into subsequent output.
+func (c *Condition) TruncateANSI(s string, w int, tail string) string {
+	if c.StringWidthANSI(s) <= w && (c.ANSIControls != ControlIgnore || indexControl(s) == -1) {
+		return s
+	}
+	tw := c.StringWidthANSI(tail)
+	if tw > w {
+		tail, tw = c.TruncateANSI(tail, w, ""), w
+	}
+
+	var (
+		b     = make([]byte, 0, len(s))
+		sgr   sgrState
+		link  bool
+		width int
+		line  int                                  // Width at the start of the current line.
+		tabs  = strings.IndexByte(tail, '\t') >= 0 // Width of tail depends on the column.
+	)
+loop:
+	for i := 0; i < len(s); {
+		if s[i] == '\n' {
+			line = width
+		}
+		n, cw, kind := c.ansiUnit(s[i:], width-line)
+		switch kind {
+		case ansiControl:
+			i += n
+			continue
+		case ansiEscape:
+			switch seq := s[i : i+n]; {
+			case isSGR(seq):
+				sgr.add(seq)
+			case isHyperlink(seq):
+				link = !strings.HasSuffix(strings.TrimRight(seq, "\x07\x1b\\"), ";")
+			}
+		default:
+			if tabs {
+				tw = c.widthANSIAt(tail, width+cw-line)
+			}
+			if width+cw+tw > w {
+				break loop
+			}
+		}
+		b = append(b, s[i:i+n]...)
+		width += cw
+		i += n
+	}
+	b = append(b, tail...)
+	if len(sgr) > 0 {
+		b = append(b, sgrReset...)
+	}
+	if link {
+		b = append(b, hyperlinkEnd...)
+	}
+	return string(b)
+}
+
+// WrapANSI is like Wrap, but skips ANSI escape sequences. Control characters
+// are handled according to ANSIControls.
+//
+// Active SGR attributes (colours, bold, etc.) are reset at the end of every
+// line that's broken and set again at the start of the next line, so every
+// line can be displayed on its own.
+func (c *Condition) WrapANSI(s string, w int) string {
+	if w <= 0 {
+		return s
+	}
+	var (
+		b     = make([]byte, 0, len(s)+len(s)/8)
+		sgr   sgrState
+		width int
+	)
+	for i := 0; i < len(s); {
+		if n := c.lineBreak(s[i:]); n > 0 {
+			b = append(b, s[i:i+n]...)
+			width = 0
+			i += n
+			continue
+		}
+		n, cw, kind := c.ansiUnit(s[i:], width)
+		switch {
+		case kind == ansiControl:
+			i += n
+			continue
+		case kind == ansiEscape:
+			if seq := s[i : i+n]; isSGR(seq) {
+				sgr.add(seq)
+			}
+		case width+cw > w && width > 0:
+			if len(sgr) > 0 {
+				b = append(b, sgrReset...)
+			}
+			b = append(b, '\n')
+			for _, seq := range sgr {
+				b = append(b, seq...)
+			}
+			width = 0
+			cw = c.tabStop(s[i], 0, cw)
+		}
+		b = append(b, s[i:i+n]...)
+		width += cw
+		i += n
+	}
+	return string(b)
+}
+
+// StringWidthANSI returns the number of cells in s, ignoring ANSI escape
+// sequences.
+func StringWidthANSI(s string) int {
+	return DefaultCondition.StringWidthANSI(s)
+}
+
+// TruncateANSI is like Truncate, but skips ANSI escape sequences.
+func TruncateANSI(s string, w int, tail string) string {
+	return DefaultCondition.TruncateANSI(s, w, tail)
+}
+
+// WrapANSI is like Wrap, but skips ANSI escape sequences.
+func WrapANSI(s string, w int) string {
+	return DefaultCondition.WrapANSI(s, w)
+}
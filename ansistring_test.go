@@ -0,0 +1,109 @@
+package runewidth
+
+import "testing"
+
+func TestStringWidthANSI(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"", 0},
+		{"abc", 3},
+		{"\x1b[31mabc\x1b[0m", 3},
+		{"\x1b]8;;http://example.com\x1b\\link\x1b]8;;\x1b\\", 4},
+		{"\x1b[1m世界\x1b[m", 4},
+		{"a\x1b_Ga=T,c=3;AAAA\x1b\\b", 2},
+	}
+
+	c := newCond(false)
+	for _, tt := range tests {
+		if have := c.StringWidthANSI(tt.in); have != tt.want {
+			t.Errorf("StringWidthANSI(%q) = %d, want %d", tt.in, have, tt.want)
+		}
+	}
+
+	c.ImageWidth = func(seq string) int { n, _ := ImageCells(seq); return n }
+	if have := c.StringWidthANSI("a\x1b_Ga=T,c=3;AAAA\x1b\\b"); have != 5 {
+		t.Errorf("StringWidthANSI with image = %d, want 5", have)
+	}
+}
+
+func TestTruncateANSI(t *testing.T) {
+	tests := []struct {
+		in   string
+		w    int
+		tail string
+		want string
+	}{
+		{"abc", 3, "…", "abc"},
+		{"\x1b[31mabcdef\x1b[0m", 6, "…", "\x1b[31mabcdef\x1b[0m"},
+		{"\x1b[31mabcdef\x1b[0m", 4, "…", "\x1b[31mabc…\x1b[0m"},
+		{"ab\x1b[31mcdef\x1b[0m", 2, "", "ab\x1b[31m\x1b[0m"},
+		{"ab\x1b[31mcd\x1b[0mef", 5, "…", "ab\x1b[31mcd\x1b[0m…"},
+		{"\x1b[1m世界\x1b[m", 3, "", "\x1b[1m世\x1b[0m"},
+		{"abcdef", 2, "\x1b[2m...\x1b[0m", "\x1b[2m..\x1b[0m"},
+		{"\x1b[38;5;200mab\x1b[1mcdef\x1b[0m", 4, "…", "\x1b[38;5;200mab\x1b[1mc…\x1b[0m"},
+		{"\x1b]8;;http://x\x1b\\link\x1b]8;;\x1b\\", 3, "…", "\x1b]8;;http://x\x1b\\li…\x1b]8;;\x1b\\"},
+		{"\x1b]8;;http://x\alink\x1b]8;;\a!!", 5, "…", "\x1b]8;;http://x\alink\x1b]8;;\a…"},
+	}
+
+	c := newCond(false)
+	for _, tt := range tests {
+		if have := c.TruncateANSI(tt.in, tt.w, tt.tail); have != tt.want {
+			t.Errorf("TruncateANSI(%q, %d, %q)\nhave: %q\nwant: %q", tt.in, tt.w, tt.tail, have, tt.want)
+		}
+	}
+}
+
+func TestWrapANSI(t *testing.T) {
+	tests := []struct {
+		in   string
+		w    int
+		want string
+	}{
+		{"abcdef", 3, "abc\ndef"},
+		{"\x1b[31mabcdef\x1b[0m", 3, "\x1b[31mabc\x1b[0m\n\x1b[31mdef\x1b[0m"},
+		{"ab\x1b[1mcd\x1b[0mef", 3, "ab\x1b[1mc\x1b[0m\n\x1b[1md\x1b[0mef"},
+		{"\x1b[31mab\ncd\x1b[0m", 3, "\x1b[31mab\ncd\x1b[0m"},
+		{"\x1b[31mab", 0, "\x1b[31mab"},
+	}
+
+	c := newCond(false)
+	for _, tt := range tests {
+		if have := c.WrapANSI(tt.in, tt.w); have != tt.want {
+			t.Errorf("WrapANSI(%q, %d)\nhave: %q\nwant: %q", tt.in, tt.w, have, tt.want)
+		}
+	}
+}
+
+func TestANSIControls(t *testing.T) {
+	tests := []struct {
+		policy   ControlPolicy
+		width    int
+		truncate string
+		fits     string
+		wrap     string
+	}{
+		{ControlZero, 8, "ab\ac", "ab\acd\bef", "ab\a\ncd\b\nef"},
+		{ControlIgnore, 6, "abcd", "abcdef", "abc\ndef"},
+		{ControlReject, 8, "ab\ac", "ab\acd\bef", "ab\a\ncd\b\nef"},
+	}
+
+	const in = "ab\acd\bef"
+	for _, tt := range tests {
+		c := newCond(false)
+		c.ControlWidth, c.ANSIControls = 1, tt.policy
+		if have := c.StringWidthANSI(in); have != tt.width {
+			t.Errorf("StringWidthANSI with %d: have %d, want %d", tt.policy, have, tt.width)
+		}
+		if have := c.TruncateANSI(in, 4, ""); have != tt.truncate {
+			t.Errorf("TruncateANSI with %d\nhave: %q\nwant: %q", tt.policy, have, tt.truncate)
+		}
+		if have := c.TruncateANSI(in, 10, ""); have != tt.fits {
+			t.Errorf("TruncateANSI with %d\nhave: %q\nwant: %q", tt.policy, have, tt.fits)
+		}
+		if have := c.WrapANSI(in, 3); have != tt.wrap {
+			t.Errorf("WrapANSI with %d\nhave: %q\nwant: %q", tt.policy, have, tt.wrap)
+		}
+	}
+}
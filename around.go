@@ -0,0 +1,91 @@
+package runewidth
+
+// TruncateAround returns a part of s that's at most w cells wide and contains
+// the character at the byte offset focus, adding tail to the side(s) where text
+// was removed.
+//
+// The window is centred around the focus character as much as possible. This
+// is useful for displaying search results:
+//
+//	TruncateAround("the quick brown fox jumps over the lazy dog", 11, 16, "…") // "…own fox j…"
+//
+// An out of range focus is clamped to the start or end of s.
+func (c *Condition) TruncateAround(s string, w, focus int, tail string) string {
+	if c.StringWidth(s) <= w {
+		return s
+	}
+	if w <= 0 {
+		return ""
+	}
+
+	// Tabs are counted as the full tab width, as the columns change when
+	// removing text.
+	type unit struct{ off, end, width int }
+	units := make([]unit, 0, len(s))
+	iter := c.clusters()
+	for i := 0; i < len(s); {
+		n, cw := iter.at(s, i, 0)
+		if cw == 0 && len(units) > 0 {
+			units[len(units)-1].end = i + n
+		} else {
+			units = append(units, unit{off: i, end: i + n, width: cw})
+		}
+		i += n
+	}
+
+	f := 0
+	for f < len(units)-1 && units[f+1].off <= focus {
+		f++
+	}
+
+	var (
+		ltw = c.StringWidth(tail) // Tail at the start.
+		rtw = c.maxWidth(tail)    // Tail at the end, which can be at any column.
+	)
+	fits := func(lo, hi, width int) bool {
+		if lo > 0 {
+			width += ltw
+		}
+		if hi < len(units)-1 {
+			width += rtw
+		}
+		return width <= w
+	}
+
+	lo, hi := f, f
+	lw, rw := 0, 0
+	for {
+		canL := lo > 0 && fits(lo-1, hi, units[f].width+lw+rw+units[lo-1].width)
+		canR := hi < len(units)-1 && fits(lo, hi+1, units[f].width+lw+rw+units[hi+1].width)
+		switch {
+		case canL && (lw <= rw || !canR):
+			lo--
+			lw += units[lo].width
+			continue
+		case canR:
+			hi++
+			rw += units[hi].width
+			continue
+		}
+		break
+	}
+	if !fits(lo, hi, units[f].width+lw+rw) {
+		return c.Truncate(s[units[f].off:], w, "")
+	}
+
+	b := make([]byte, 0, units[hi].end-units[lo].off+len(tail)*2)
+	if lo > 0 {
+		b = append(b, tail...)
+	}
+	b = append(b, s[units[lo].off:units[hi].end]...)
+	if hi < len(units)-1 {
+		b = append(b, tail...)
+	}
+	return string(b)
+}
+
+// TruncateAround returns a part of s that's at most w cells wide and contains
+// the character at the byte offset focus.
+func TruncateAround(s string, w, focus int, tail string) string {
+	return DefaultCondition.TruncateAround(s, w, focus, tail)
+}
@@ -0,0 +1,37 @@
+package runewidth
+
+import (
+	"testing"
+)
+
+func TestTruncateAround(t *testing.T) {
+	tests := []struct {
+		in       string
+		w, focus int
+		tail     string
+		want     string
+	}{
+		{"", 5, 0, "…", ""},
+		{"short", 5, 2, "…", "short"},
+		{"the quick brown fox jumps over the lazy dog", 11, 16, "…", "…own fox j…"},
+		{"the quick brown fox jumps over the lazy dog", 11, 0, "…", "the quick …"},
+		{"the quick brown fox jumps over the lazy dog", 11, -5, "…", "the quick …"},
+		{"the quick brown fox jumps over the lazy dog", 11, 42, "…", "…e lazy dog"},
+		{"the quick brown fox jumps over the lazy dog", 11, 99, "…", "…e lazy dog"},
+		{"the quick brown fox jumps over the lazy dog", 11, 16, "", "rown fox ju"},
+		{"あいうえおかきくけこ", 8, 15, "…", "…おかき…"},
+		{"abcdef", 1, 3, "…", "d"},
+		{"ab́cdef", 3, 1, "…", "ab́…"},
+	}
+
+	c := newCond(false)
+	for _, tt := range tests {
+		if have := c.TruncateAround(tt.in, tt.w, tt.focus, tt.tail); have != tt.want {
+			t.Errorf("TruncateAround(%q, %d, %d, %q)\nhave: %q\nwant: %q",
+				tt.in, tt.w, tt.focus, tt.tail, have, tt.want)
+		}
+		if w := c.StringWidth(c.TruncateAround(tt.in, tt.w, tt.focus, tt.tail)); w > tt.w {
+			t.Errorf("too wide: %d", w)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+// Package bench provides a benchmark corpus and harness for runewidth.
+//
+// This can be used to measure the performance impact of Condition settings
+// and package upgrades on your own hardware, and to detect changes in the
+// results:
+//
+//	func BenchmarkWidth(b *testing.B) {
+//		bench.Run(b, runewidth.NewCondition())
+//	}
+package bench
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+	"testing"
+
+	"zgo.at/runewidth"
+)
+
+// Corpus is a text to benchmark with.
+type Corpus struct {
+	Name string
+	Text string
+}
+
+// Lines returns the lines in the corpus.
+func (c Corpus) Lines() []string { return strings.Split(c.Text, "\n") }
+
+// size is the approximate size of every corpus in bytes.
+const size = 16 * 1024
+
+var samples = map[string][]string{
+	"ascii": {
+		"The quick brown fox jumps over the lazy dog.",
+		"func main() { fmt.Println(\"Hello, world\") }",
+		"-rw-r--r--  1 martin  staff   4096 Jan  1 12:00 README.md",
+	},
+	"cjk": {
+		"日本語のテキストを表示するためのサンプルです。",
+		"中文文本的宽度计算需要考虑全角字符。",
+		"한국어 텍스트도 넓은 문자로 표시됩니다.",
+	},
+	"emoji": {
+		"Great job! 👍🎉 Let's ship it 🚀🚀",
+		"❤️ 🇳🇱 👨‍👩‍👧 🏳️‍🌈 ✌🏽 ☺️ #️⃣",
+		"Weather: ☀️ 🌧 ⛈ ❄️ 🌈",
+	},
+	"ansi": {
+		"\x1b[1;31merror\x1b[0m: file \x1b[4mmain.go\x1b[0m not found",
+		"\x1b[38;5;208m■\x1b[0m \x1b[32mPASS\x1b[0m \x1b]8;;https://example.com\x1b\\link\x1b]8;;\x1b\\",
+		"\x1b[2K\x1b[1G\x1b[7m 42% \x1b[27m [\x1b[32m=========>\x1b[0m          ]",
+	},
+	"mixed": {
+		"Résumé: naïve café, Ελληνικά, Русский, עברית",
+		"Status: ✔ 完了 (3/4) — \x1b[33mwarning\x1b[0m 😅",
+		"é ä ｶﾀｶﾅ ＡＢＣ ①②③ ☆★",
+	},
+}
+
+var corpora []Corpus
+
+func init() {
+	names := make([]string, 0, len(samples))
+	for n := range samples {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		var b strings.Builder
+		b.Grow(size + 128)
+		for i := 0; b.Len() < size; i++ {
+			if i > 0 {
+				b.WriteByte('\n')
+			}
+			b.WriteString(samples[n][i%len(samples[n])])
+		}
+		corpora = append(corpora, Corpus{Name: n, Text: b.String()})
+	}
+}
+
+// Corpora returns the built-in corpora: "ansi" (text with ANSI escape
+// sequences), "ascii", "cjk", "emoji", and "mixed". Every corpus is about
+// 16K.
+func Corpora() []Corpus {
+	return append([]Corpus(nil), corpora...)
+}
+
+// Op is an operation to benchmark.
+type Op struct {
+	Name string
+	Fn   func(c *runewidth.Condition, line string)
+}
+
+// Ops returns the operations that Run and Measure benchmark.
+func Ops() []Op {
+	return []Op{
+		{"StringWidth", func(c *runewidth.Condition, l string) { c.StringWidth(l) }},
+		{"StringWidthANSI", func(c *runewidth.Condition, l string) { c.StringWidthANSI(l) }},
+		{"Truncate", func(c *runewidth.Condition, l string) { c.Truncate(l, 20, "…") }},
+		{"Wrap", func(c *runewidth.Condition, l string) { c.Wrap(l, 20) }},
+	}
+}
+
+// Run runs sub-benchmarks for all operations and corpora with the Condition
+// c, named "op/corpus".
+func Run(b *testing.B, c *runewidth.Condition) {
+	for _, op := range Ops() {
+		for _, corpus := range corpora {
+			b.Run(op.Name+"/"+corpus.Name, bench(c, op, corpus))
+		}
+	}
+}
+
+func bench(c *runewidth.Condition, op Op, corpus Corpus) func(*testing.B) {
+	lines := corpus.Lines()
+	return func(b *testing.B) {
+		b.SetBytes(int64(len(corpus.Text)))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, l := range lines {
+				op.Fn(c, l)
+			}
+		}
+	}
+}
+
+// Result is the result of a benchmark.
+type Result struct {
+	Op, Corpus string
+	testing.BenchmarkResult
+}
+
+func (r Result) String() string {
+	return fmt.Sprintf("%-16s %-6s %s", r.Op, r.Corpus, r.BenchmarkResult.String())
+}
+
+// Measure runs the benchmarks for all operations and corpora with the
+// Condition c outside of "go test", for example to compare settings in a
+// program. This takes about a second for every result.
+func Measure(c *runewidth.Condition) []Result {
+	var res []Result
+	for _, op := range Ops() {
+		for _, corpus := range corpora {
+			res = append(res, Result{op.Name, corpus.Name, testing.Benchmark(bench(c, op, corpus))})
+		}
+	}
+	return res
+}
+
+// Checksums returns a checksum of the widths of all lines in every corpus
+// with the Condition c, keyed by the corpus name.
+//
+// This can be used in a test to detect if an upgrade or a change in the
+// settings changes the results.
+func Checksums(c *runewidth.Condition) map[string]uint32 {
+	sums := make(map[string]uint32, len(corpora))
+	for _, corpus := range corpora {
+		h := fnv.New32a()
+		for _, l := range corpus.Lines() {
+			fmt.Fprintf(h, "%d,%d;", c.StringWidth(l), c.StringWidthANSI(l))
+		}
+		sums[corpus.Name] = h.Sum32()
+	}
+	return sums
+}
@@ -0,0 +1,47 @@
+package bench
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"zgo.at/runewidth"
+)
+
+func TestCorpora(t *testing.T) {
+	var names []string
+	for _, c := range Corpora() {
+		names = append(names, c.Name)
+		if len(c.Text) < size || len(c.Text) > size+128 {
+			t.Errorf("%s: size is %d", c.Name, len(c.Text))
+		}
+		if strings.HasSuffix(c.Text, "\n") {
+			t.Errorf("%s: ends with newline", c.Name)
+		}
+	}
+	if want := []string{"ansi", "ascii", "cjk", "emoji", "mixed"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("names: %q", names)
+	}
+}
+
+func TestChecksums(t *testing.T) {
+	c := runewidth.NewCondition()
+	c.EastAsianWidth = false
+	a, b := Checksums(c), Checksums(c)
+	if !reflect.DeepEqual(a, b) {
+		t.Errorf("not stable:\n%v\n%v", a, b)
+	}
+
+	c.EastAsianWidth = true
+	ea := Checksums(c)
+	if ea["ascii"] != a["ascii"] {
+		t.Errorf("ascii changed with EastAsianWidth")
+	}
+	if ea["mixed"] == a["mixed"] {
+		t.Errorf("mixed didn't change with EastAsianWidth")
+	}
+}
+
+func BenchmarkDefault(b *testing.B) {
+	Run(b, runewidth.NewCondition())
+}
@@ -1,6 +1,7 @@
 package runewidth
 
 import (
+	"strings"
 	"testing"
 	"unicode/utf8"
 )
@@ -102,3 +103,85 @@ func BenchmarkTableNarrow(b *testing.B) {
 func BenchmarkTableNeutral(b *testing.B) {
 	benchSink = benchTable(b, neutral)
 }
+
+func BenchmarkCreateLUT(b *testing.B) {
+	c := NewCondition()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		c.combinedLut = nil
+		c.CreateLUT()
+	}
+}
+
+// Lookups with a LUT shouldn't get slower with more options set.
+func BenchmarkCreateLUTRuneWidth(b *testing.B) {
+	c := NewCondition()
+	c.UnicodeVersion = "13.0.0"
+	c.AmbiguousLocale = "ja"
+	c.NerdFonts = true
+	c.SetWidth('★', 2)
+	c.CreateLUT()
+	n := 0
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for r := rune(0); r < 0x300; r++ {
+			n += c.RuneWidth(r)
+		}
+	}
+	benchSink = n
+}
+
+func BenchmarkAlphabet(b *testing.B) {
+	const chars = "abcdefghijklmnopqrstuvwxyz0123456789 -:.[]日付時刻"
+	b.Run("alphabet", func(b *testing.B) {
+		a := NewCondition().NewAlphabet(chars)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			for _, r := range chars {
+				benchSink += a.RuneWidth(r)
+			}
+		}
+	})
+	b.Run("condition", func(b *testing.B) {
+		c := NewCondition()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			for _, r := range chars {
+				benchSink += c.RuneWidth(r)
+			}
+		}
+	})
+}
+
+func BenchmarkCompiled(b *testing.B) {
+	cc := NewCondition().Compile()
+	n := 0
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for r := rune(0); r <= utf8.MaxRune; r++ {
+			n += cc.RuneWidth(r)
+		}
+	}
+	benchSink = n
+}
+
+func BenchmarkString(b *testing.B) {
+	s := strings.Repeat("The quick brown fox 日本語のテキスト héllo wörld ", 20)
+	c := NewCondition()
+	b.Run("StringWidth", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			benchSink += c.StringWidth(s)
+		}
+	})
+	b.Run("Truncate", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			benchSink += len(c.Truncate(s, 500, "…"))
+		}
+	})
+	b.Run("Wrap", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			benchSink += len(c.Wrap(s, 40))
+		}
+	})
+}
@@ -0,0 +1,45 @@
+package runewidth
+
+import "sync"
+
+// newBufPool creates a pool of scratch buffers for building strings, so that
+// functions such as Wrap and FillLeft only need to allocate the final string.
+//
+// Every Condition created with NewCondition or Profile.Condition has its own
+// pool; sync.Pool keeps per-P caches, so this doesn't add contention when a
+// Condition is used from several goroutines.
+func newBufPool() *sync.Pool {
+	return &sync.Pool{
+		New: func() interface{} {
+			b := make([]byte, 0, 256)
+			return &b
+		},
+	}
+}
+
+// sharedBufs is used for Conditions that weren't created with NewCondition,
+// such as a Condition{} literal.
+var sharedBufs = newBufPool()
+
+// maxPooled is the largest buffer that's returned to the pool, so a single
+// call with a very large string doesn't keep that memory around.
+const maxPooled = 64 << 10
+
+func (c *Condition) getBuf() *[]byte {
+	b := c.pool().Get().(*[]byte)
+	*b = (*b)[:0]
+	return b
+}
+
+func (c *Condition) putBuf(b *[]byte) {
+	if cap(*b) <= maxPooled {
+		c.pool().Put(b)
+	}
+}
+
+func (c *Condition) pool() *sync.Pool {
+	if c.bufs == nil {
+		return sharedBufs
+	}
+	return c.bufs
+}
@@ -0,0 +1,192 @@
+package runewidth
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrOverflow is returned by the Builder's write methods if the text doesn't
+// fit in the Budget and the Overflow policy is OverflowError.
+var ErrOverflow = errors.New("runewidth: text doesn't fit in budget")
+
+// OverflowPolicy sets what a Builder does with text that doesn't fit in the
+// Budget.
+type OverflowPolicy uint8
+
+const (
+	// OverflowStop writes as much as fits and ignores all further writes.
+	OverflowStop OverflowPolicy = iota
+
+	// OverflowTruncate is like OverflowStop, but the text is truncated with
+	// the Tail.
+	OverflowTruncate
+
+	// OverflowError doesn't write anything and returns ErrOverflow, so the
+	// caller can try writing something shorter.
+	OverflowError
+)
+
+// Builder is a strings.Builder that keeps track of the width of the text
+// written to it.
+//
+// The zero value is ready to use, and uses DefaultCondition. Like
+// strings.Builder, a Builder should not be copied after first use.
+type Builder struct {
+	// Condition to use; DefaultCondition is used if nil.
+	Condition *Condition
+
+	// Budget is the maximum width of the text; there is no maximum if this
+	// is 0. Overflow sets what happens with text that doesn't fit, and Tail
+	// is used for OverflowTruncate.
+	//
+	// With OverflowStop and OverflowTruncate the write methods always
+	// report that all of the input was written, even if it was discarded.
+	Budget   int
+	Overflow OverflowPolicy
+	Tail     string
+
+	b     strings.Builder
+	width int  // Width of b[:last].
+	last  int  // Offset of the last character, which may still be extended.
+	lastW int  // Width of the last character.
+	line  int  // Width at the start of the current line, for tab stops.
+	full  bool // Text was truncated; ignore further writes.
+}
+
+func (b *Builder) cond() *Condition {
+	if b.Condition == nil {
+		return DefaultCondition
+	}
+	return b.Condition
+}
+
+// update the width after a write. The last character is measured again, since
+// the write may have added combining characters or a variation selector to it.
+// An incomplete UTF-8 sequence at the end isn't measured until it's complete.
+func (b *Builder) update() {
+	c, s := b.cond(), b.b.String()
+	i := b.last
+	for i < len(s) && utf8.FullRuneInString(s[i:]) {
+		if s[i] == '\n' {
+			b.line = b.width
+		}
+		n, w := c.clusterAt(s[i:], b.width-b.line)
+		if i+n >= len(s) || !utf8.FullRuneInString(s[i+n:]) {
+			break
+		}
+		b.width += w
+		i += n
+	}
+	b.last, b.lastW = i, 0
+	for j, line := i, b.line; j < len(s) && utf8.FullRuneInString(s[j:]); {
+		if s[j] == '\n' {
+			line = b.width + b.lastW
+		}
+		n, w := c.clusterAt(s[j:], b.width+b.lastW-line)
+		b.lastW += w
+		j += n
+	}
+}
+
+// budgeted writes s if there is a Budget.
+func (b *Builder) budgeted(s string) (int, error) {
+	if b.full {
+		return len(s), nil
+	}
+	var (
+		prev                     = b.b.String()
+		width, last, lastW, line = b.width, b.last, b.lastW, b.line
+	)
+	b.b.WriteString(s)
+	b.update()
+	if b.Width() <= b.Budget {
+		return len(s), nil
+	}
+
+	// Doesn't fit: go back to what was there before the write. The
+	// strings.Builder never modifies bytes that were written, so prev is
+	// still valid.
+	all := b.b.String()
+	b.b.Reset()
+	b.b.WriteString(prev)
+	b.width, b.last, b.lastW, b.line = width, last, lastW, line
+	if b.Overflow == OverflowError {
+		return 0, ErrOverflow
+	}
+	var tail string
+	if b.Overflow == OverflowTruncate {
+		tail = b.Tail
+	}
+	t := b.cond().Truncate(all, b.Budget, tail)
+	b.Reset()
+	b.b.WriteString(t)
+	b.update()
+	b.full = true
+	return len(s), nil
+}
+
+// Full reports if text was discarded because it didn't fit in the Budget.
+func (b *Builder) Full() bool { return b.full }
+
+// Width returns the width of the text written so far. An incomplete UTF-8
+// sequence at the end isn't counted until the rest of it is written.
+func (b *Builder) Width() int { return b.width + b.lastW }
+
+// String returns the accumulated string.
+func (b *Builder) String() string { return b.b.String() }
+
+// Len returns the number of accumulated bytes.
+func (b *Builder) Len() int { return b.b.Len() }
+
+// Grow grows the capacity by at least n bytes; see strings.Builder.Grow.
+func (b *Builder) Grow(n int) { b.b.Grow(n) }
+
+// Reset resets the Builder to be empty.
+func (b *Builder) Reset() {
+	b.b.Reset()
+	b.width, b.last, b.lastW, b.line, b.full = 0, 0, 0, 0, false
+}
+
+// Write appends the contents of p. It returns len(p), nil unless the text
+// doesn't fit in the Budget.
+func (b *Builder) Write(p []byte) (int, error) {
+	if b.Budget > 0 {
+		return b.budgeted(string(p))
+	}
+	b.b.Write(p)
+	b.update()
+	return len(p), nil
+}
+
+// WriteByte appends the byte c.
+func (b *Builder) WriteByte(c byte) error {
+	if b.Budget > 0 {
+		_, err := b.budgeted(string([]byte{c}))
+		return err
+	}
+	b.b.WriteByte(c)
+	b.update()
+	return nil
+}
+
+// WriteRune appends the UTF-8 encoding of r.
+func (b *Builder) WriteRune(r rune) (int, error) {
+	if b.Budget > 0 {
+		return b.budgeted(string(r))
+	}
+	n, _ := b.b.WriteRune(r)
+	b.update()
+	return n, nil
+}
+
+// WriteString appends the contents of s. It returns len(s), nil unless the
+// text doesn't fit in the Budget.
+func (b *Builder) WriteString(s string) (int, error) {
+	if b.Budget > 0 {
+		return b.budgeted(s)
+	}
+	b.b.WriteString(s)
+	b.update()
+	return len(s), nil
+}
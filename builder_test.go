@@ -0,0 +1,136 @@
+package runewidth
+
+import "testing"
+
+func TestBuilder(t *testing.T) {
+	tests := []struct {
+		writes []string
+		want   int
+	}{
+		{nil, 0},
+		{[]string{"abc"}, 3},
+		{[]string{"a", "世", "b"}, 4},
+		{[]string{"e", "́", "x"}, 2},
+		{[]string{"a", "⃝"}, 2},
+		{[]string{"❤", "︎", "❤", "️"}, 3},
+		{[]string{"🇳", "🇱", "🇯", "🇵"}, 4},
+		{[]string{"🇳🇱🇯", "🇵a"}, 5},
+	}
+
+	for _, tt := range tests {
+		c := newCond(false)
+		c.WideEnclosing, c.VariationSelectors, c.RegionalIndicators = true, true, true
+
+		b := Builder{Condition: c}
+		var all string
+		for _, w := range tt.writes {
+			b.WriteString(w)
+			all += w
+		}
+		if have := b.Width(); have != tt.want {
+			t.Errorf("%q: Width() = %d, want %d", tt.writes, have, tt.want)
+		}
+		if have := c.StringWidth(all); have != tt.want {
+			t.Errorf("%q: StringWidth() = %d, want %d", tt.writes, have, tt.want)
+		}
+		if b.String() != all || b.Len() != len(all) {
+			t.Errorf("%q: String() = %q", tt.writes, b.String())
+		}
+	}
+}
+
+func TestBuilderWrite(t *testing.T) {
+	var b Builder
+	b.WriteByte('a')
+	b.WriteRune('世')
+	b.Write([]byte("bc"))
+	if b.Width() != 5 || b.String() != "a世bc" {
+		t.Errorf("have %q with width %d", b.String(), b.Width())
+	}
+
+	// Split UTF-8 sequences.
+	b.Reset()
+	for _, c := range []byte("世界") {
+		b.WriteByte(c)
+	}
+	b.Write([]byte("é")[:1])
+	b.Write([]byte("é")[1:])
+	if b.Width() != 5 || b.String() != "世界é" {
+		t.Errorf("have %q with width %d", b.String(), b.Width())
+	}
+
+	b.Reset()
+	if b.Width() != 0 || b.String() != "" {
+		t.Errorf("have %q with width %d after Reset", b.String(), b.Width())
+	}
+	b.WriteString("xy")
+	if b.Width() != 2 {
+		t.Errorf("have width %d after Reset", b.Width())
+	}
+}
+
+func TestBuilderBudget(t *testing.T) {
+	tests := []struct {
+		policy OverflowPolicy
+		writes []string
+		want   string
+		full   bool
+		errs   int
+	}{
+		{OverflowStop, []string{"ab", "cd"}, "abcd", false, 0},
+		{OverflowStop, []string{"ab", "cdef", "g"}, "abcde", true, 0},
+		{OverflowStop, []string{"abc", "世界"}, "abc世", true, 0},
+		{OverflowTruncate, []string{"ab", "cdef", "g"}, "abcd…", true, 0},
+		{OverflowTruncate, []string{"abcde", "f"}, "abcd…", true, 0},
+		{OverflowError, []string{"ab", "cdef", "cde", "f"}, "abcde", false, 2},
+	}
+
+	for _, tt := range tests {
+		b := Builder{Budget: 5, Overflow: tt.policy, Tail: "…"}
+		var errs int
+		for _, w := range tt.writes {
+			if _, err := b.WriteString(w); err == ErrOverflow {
+				errs++
+			} else if err != nil {
+				t.Fatal(err)
+			}
+		}
+		if b.String() != tt.want || b.Full() != tt.full || errs != tt.errs {
+			t.Errorf("%d %q: have (%q, %t, %d), want (%q, %t, %d)", tt.policy, tt.writes,
+				b.String(), b.Full(), errs, tt.want, tt.full, tt.errs)
+		}
+		if b.Width() > 5 {
+			t.Errorf("%d %q: width %d", tt.policy, tt.writes, b.Width())
+		}
+	}
+
+	b := Builder{Budget: 4, Overflow: OverflowStop}
+	for _, c := range []byte("世界!") {
+		b.WriteByte(c)
+	}
+	if b.String() != "世界" || !b.Full() {
+		t.Errorf("have %q, full %t", b.String(), b.Full())
+	}
+
+	b = Builder{Budget: 3, Overflow: OverflowError}
+	for _, c := range []byte("ab€") {
+		if err := b.WriteByte(c); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	b = Builder{Budget: 1000}
+	b.Grow(1000)
+	if n := testing.AllocsPerRun(100, func() { b.WriteString("ab") }); n != 0 {
+		t.Errorf("%.0f allocations per write", n)
+	}
+
+	b = Builder{Budget: 2, Overflow: OverflowError}
+	b.WriteRune('世')
+	if err := b.WriteByte('x'); err != ErrOverflow {
+		t.Errorf("WriteByte: wrong error: %v", err)
+	}
+	if _, err := b.Write([]byte("x")); err != ErrOverflow {
+		t.Errorf("Write: wrong error: %v", err)
+	}
+}
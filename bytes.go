@@ -0,0 +1,78 @@
+package runewidth
+
+import (
+	"unicode/utf8"
+	"unsafe"
+)
+
+// bytesToString converts b to a string without copying. The string must not be
+// retained after the call that it's passed to, as b may be modified.
+func bytesToString(b []byte) string {
+	return *(*string)(unsafe.Pointer(&b))
+}
+
+// sameString reports if a == b, without comparing the bytes if a and b point
+// to the same memory.
+func sameString(a, b string) bool {
+	return len(a) == len(b) &&
+		(*(*uintptr)(unsafe.Pointer(&a)) == *(*uintptr)(unsafe.Pointer(&b)) || a == b)
+}
+
+// RuneWidthInBytes returns the width of the first rune in b and its length in
+// bytes. An invalid UTF-8 byte is 1 byte with the width of U+FFFD, like in
+// StringWidthBytes, and an empty b is 0 cells and 0 bytes.
+func (c *Condition) RuneWidthInBytes(b []byte) (width, size int) {
+	r, size := utf8.DecodeRune(b)
+	if size == 0 {
+		return 0, 0
+	}
+	return c.RuneWidth(r), size
+}
+
+// StringWidthBytes is like StringWidth, but for a byte slice.
+func (c *Condition) StringWidthBytes(b []byte) int {
+	return c.StringWidth(bytesToString(b))
+}
+
+// TruncateBytes is like Truncate, but for a byte slice.
+//
+// If no tail is appended the result is b, or b sliced to the truncated length;
+// modifying it will modify b. Otherwise the result is a new slice.
+func (c *Condition) TruncateBytes(b []byte, w int, tail string) []byte {
+	s := bytesToString(b)
+	if c.StringWidth(s) <= w {
+		return b
+	}
+	pos, tail, _, _ := c.truncateAt(s, w, tail)
+	if tail == "" {
+		return b[:pos]
+	}
+	return append(append(make([]byte, 0, pos+len(tail)), b[:pos]...), tail...)
+}
+
+// WrapBytes is like Wrap, but for a byte slice. The result is always a new
+// slice; use AppendWrap to reuse a buffer.
+func (c *Condition) WrapBytes(b []byte, w int) []byte {
+	return c.AppendWrap(make([]byte, 0, len(b)), bytesToString(b), w)
+}
+
+// RuneWidthInBytes returns the width of the first rune in b and its length in
+// bytes.
+func RuneWidthInBytes(b []byte) (width, size int) {
+	return DefaultCondition.RuneWidthInBytes(b)
+}
+
+// StringWidthBytes is like StringWidth, but for a byte slice.
+func StringWidthBytes(b []byte) int {
+	return DefaultCondition.StringWidthBytes(b)
+}
+
+// TruncateBytes is like Truncate, but for a byte slice.
+func TruncateBytes(b []byte, w int, tail string) []byte {
+	return DefaultCondition.TruncateBytes(b, w, tail)
+}
+
+// WrapBytes is like Wrap, but for a byte slice.
+func WrapBytes(b []byte, w int) []byte {
+	return DefaultCondition.WrapBytes(b, w)
+}
@@ -0,0 +1,73 @@
+package runewidth
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestRuneWidthInBytes(t *testing.T) {
+	tests := []struct {
+		in          string
+		ea          bool
+		width, size int
+	}{
+		{"", false, 0, 0},
+		{"a", false, 1, 1},
+		{"つa", false, 2, 3},
+		{"\u0301", false, 0, 2},
+		{"\xff", false, 1, 1},
+		{"\xe3\x81", false, 1, 1},
+		{"\xff", true, 2, 1},
+		{"\xe3\x81", true, 2, 1},
+	}
+	for _, tt := range tests {
+		c := newCond(tt.ea)
+		w, n := c.RuneWidthInBytes([]byte(tt.in))
+		if w != tt.width || n != tt.size {
+			t.Errorf("%q (ea=%t): %d, %d; want %d, %d", tt.in, tt.ea, w, n, tt.width, tt.size)
+		}
+		if sw := c.StringWidthBytes([]byte(tt.in)[:n]); sw != w {
+			t.Errorf("%q (ea=%t): StringWidthBytes = %d; RuneWidthInBytes = %d", tt.in, tt.ea, sw, w)
+		}
+	}
+}
+
+func TestBytes(t *testing.T) {
+	tests := []string{"", "abc", "つのだ☆HIRO", "áb\tc", "\xffab", "line 1\nline 2 is longer"}
+	for _, ea := range []bool{false, true} {
+		c := newCond(ea)
+		for _, s := range tests {
+			if have, want := c.StringWidthBytes([]byte(s)), c.StringWidth(s); have != want {
+				t.Errorf("StringWidthBytes(%q) = %d, want %d", s, have, want)
+			}
+			for w := 0; w < 10; w++ {
+				for _, tail := range []string{"", "…", "..."} {
+					name := fmt.Sprintf("%q/%d/%q", s, w, tail)
+					if have, want := string(c.TruncateBytes([]byte(s), w, tail)), c.Truncate(s, w, tail); have != want {
+						t.Errorf("TruncateBytes(%s) = %q, want %q", name, have, want)
+					}
+				}
+				if have, want := string(c.WrapBytes([]byte(s), w)), c.Wrap(s, w); have != want {
+					t.Errorf("WrapBytes(%q, %d) = %q, want %q", s, w, have, want)
+				}
+			}
+		}
+	}
+
+	// Truncating without a tail returns part of the input.
+	b := []byte("abcdef")
+	if tr := TruncateBytes(b, 3, ""); &tr[0] != &b[0] || string(tr) != "abc" {
+		t.Errorf("TruncateBytes = %q; not a subslice", tr)
+	}
+}
+
+func TestBytesAllocs(t *testing.T) {
+	c := newCond(false)
+	b := []byte("つのだ☆HIRO")
+	if n := testing.AllocsPerRun(100, func() { c.StringWidthBytes(b) }); n != 0 {
+		t.Errorf("StringWidthBytes: %v allocations", n)
+	}
+	if n := testing.AllocsPerRun(100, func() { c.TruncateBytes(b, 5, "") }); n != 0 {
+		t.Errorf("TruncateBytes: %v allocations", n)
+	}
+}
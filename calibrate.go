@@ -0,0 +1,102 @@
+package runewidth
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// calibrations are the texts that Calibrate measures, and how to update the
+// Profile for the measured width.
+var calibrations = []struct {
+	text string
+	set  func(p *Profile, w int)
+}{
+	// U+2606 WHITE STAR (ambiguous).
+	{"☆", func(p *Profile, w int) { p.EastAsianWidth = w == 2 }},
+	// U+263A WHITE SMILING FACE (emoji with text presentation).
+	{"☺", func(p *Profile, w int) { p.StrictEmojiNeutral = w != 2 }},
+	// U+231A WATCH with VS15; this is 3 or 4 cells if the variation selector
+	// is counted as a character.
+	{"⌚\ufe0e", func(p *Profile, w int) { p.VariationSelectors = w == 1 }},
+	// "a" with U+20DD COMBINING ENCLOSING CIRCLE.
+	{"a⃝", func(p *Profile, w int) { p.WideEnclosing = w == 2 }},
+	// U+1F93B MODERN PENTATHLON (wide in Unicode 9.0 to 12.1).
+	{"\U0001f93b", func(p *Profile, w int) {
+		if w == 2 {
+			p.UnicodeVersion = "9.0.0"
+		}
+	}},
+	// U+1FAE9 FACE WITH BAGS UNDER EYES (new in Unicode 16.0); this is
+	// always wide if emoji are wide with EastAsianWidth.
+	{"\U0001fae9", func(p *Profile, w int) {
+		if w == 2 && (!p.EastAsianWidth || p.StrictEmojiNeutral) {
+			p.UnicodeVersion = "16.0.0"
+		}
+	}},
+}
+
+// Calibrate returns a Probe that measures how the terminal displays some
+// characters and updates the Profile accordingly. It sets EastAsianWidth,
+// StrictEmojiNeutral, VariationSelectors, WideEnclosing, and UnicodeVersion.
+//
+// Every character is written to tty, after which the cursor position is
+// requested with a CSI 6n escape sequence and the report is read from tty.
+// The line is cleared afterwards.
+//
+// The terminal must be in raw mode (for example with MakeRaw from
+// golang.org/x/term), as the report isn't sent until a newline otherwise.
+// Reading the report will block forever if the terminal doesn't support the
+// escape sequence.
+func Calibrate(tty io.ReadWriter) Probe {
+	return func(p *Profile) error {
+		defer io.WriteString(tty, "\r\x1b[K")
+		for _, c := range calibrations {
+			if _, err := io.WriteString(tty, "\r"+c.text+"\x1b[6n"); err != nil {
+				return fmt.Errorf("runewidth.Calibrate: %w", err)
+			}
+			col, err := readCursorColumn(tty)
+			if err != nil {
+				return fmt.Errorf("runewidth.Calibrate: %q: %w", c.text, err)
+			}
+			c.set(p, col-1)
+		}
+		return nil
+	}
+}
+
+var errCursorReport = errors.New("invalid cursor position report")
+
+// readCursorColumn reads a cursor position report ("ESC [ row ; col R") from
+// r and returns the column. It reads one byte at a time, so that nothing after
+// the report is read.
+func readCursorColumn(r io.Reader) (int, error) {
+	var (
+		buf  []byte
+		b    = make([]byte, 1)
+		semi = -1
+	)
+	for {
+		if _, err := io.ReadFull(r, b); err != nil {
+			if err == io.EOF && len(buf) > 0 {
+				err = io.ErrUnexpectedEOF
+			}
+			return 0, err
+		}
+		switch {
+		case len(buf) == 0 && b[0] != 0x1b, len(buf) == 1 && b[0] != '[':
+			continue // Skip anything before the report.
+		case b[0] == ';':
+			semi = len(buf)
+		case b[0] == 'R':
+			if semi == -1 {
+				return 0, errCursorReport
+			}
+			return strconv.Atoi(string(buf[semi+1:]))
+		case len(buf) > 16:
+			return 0, errCursorReport
+		}
+		buf = append(buf, b[0])
+	}
+}
@@ -0,0 +1,109 @@
+package runewidth
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+// fakeTerm responds to CSI 6n with the width of the text on the line,
+// measured with Condition.
+type fakeTerm struct {
+	c    *Condition
+	line string
+	resp bytes.Buffer
+}
+
+func (f *fakeTerm) Write(b []byte) (int, error) {
+	s := string(b)
+	if i := strings.LastIndexByte(s, '\r'); i > -1 {
+		f.line, s = "", s[i+1:]
+	}
+	if strings.HasSuffix(s, "\x1b[6n") {
+		f.line += strings.TrimSuffix(s, "\x1b[6n")
+		fmt.Fprintf(&f.resp, "junk\x1b[5;%dR", f.c.StringWidth(f.line)+1)
+	}
+	return len(b), nil
+}
+
+func (f *fakeTerm) Read(b []byte) (int, error) { return f.resp.Read(b) }
+
+func TestCalibrate(t *testing.T) {
+	tests := []Profile{
+		{},
+		{StrictEmojiNeutral: true},
+		{EastAsianWidth: true, StrictEmojiNeutral: true},
+		{EastAsianWidth: true, StrictEmojiNeutral: false, UnicodeVersion: "9.0.0"},
+		{EastAsianWidth: true, StrictEmojiNeutral: true, UnicodeVersion: "16.0.0"},
+		{StrictEmojiNeutral: true, VariationSelectors: true, WideEnclosing: true, UnicodeVersion: "16.0.0"},
+	}
+
+	for _, want := range tests {
+		t.Run("", func(t *testing.T) {
+			term := &fakeTerm{c: want.Condition()}
+			var have Profile
+			if err := Calibrate(term)(&have); err != nil {
+				t.Fatal(err)
+			}
+			if want.EastAsianWidth == false {
+				// Can't be detected without EastAsianWidth.
+				have.StrictEmojiNeutral = want.StrictEmojiNeutral
+			}
+			if have != want {
+				t.Errorf("\nhave: %s\nwant: %s", have, want)
+			}
+		})
+	}
+}
+
+func TestCalibrateError(t *testing.T) {
+	var (
+		buf bytes.Buffer
+		p   Profile
+	)
+	err := Calibrate(struct {
+		io.Reader
+		io.Writer
+	}{strings.NewReader("\x1b[1;2R\x1b[1;"), &buf})(&p)
+	if have, want := fmt.Sprint(err), `runewidth.Calibrate: "☺": unexpected EOF`; have != want {
+		t.Errorf("\nhave: %s\nwant: %s", have, want)
+	}
+	if !strings.HasSuffix(buf.String(), "\r\x1b[K") {
+		t.Errorf("line not cleared: %q", buf.String())
+	}
+}
+
+func TestReadCursorColumn(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int
+		wantErr string
+	}{
+		{"\x1b[1;5R", 5, ""},
+		{"abc\x1b[12;80Rxyz", 80, ""},
+		{"\x1b[5R", 0, "invalid cursor position report"},
+		{"\x1b[1;5", 0, "unexpected EOF"},
+		{"", 0, "EOF"},
+		{"\x1b[1;12345678901234567890R", 0, "invalid cursor position report"},
+		{"\x1b[1;xR", 0, `strconv.Atoi: parsing "x": invalid syntax`},
+	}
+
+	for _, tt := range tests {
+		have, err := readCursorColumn(strings.NewReader(tt.in))
+		if have := fmt.Sprint(err); err != nil && have != tt.wantErr || err == nil && tt.wantErr != "" {
+			t.Errorf("%q: wrong error: %v", tt.in, err)
+		}
+		if have != tt.want {
+			t.Errorf("%q: have %d, want %d", tt.in, have, tt.want)
+		}
+	}
+
+	// Doesn't read past the report.
+	r := strings.NewReader("\x1b[1;5Rrest")
+	readCursorColumn(r)
+	if rest, _ := io.ReadAll(r); string(rest) != "rest" {
+		t.Errorf("rest: %q", rest)
+	}
+}
@@ -0,0 +1,63 @@
+package runewidth
+
+// Cell is a single column on the screen.
+type Cell struct {
+	Offset       int  // Byte offset of the character in the string.
+	Width        int  // Width of the character.
+	Continuation bool // Second cell of a wide character.
+}
+
+// Cells describes the screen columns of a string, with one entry for every
+// column.
+type Cells []Cell
+
+// StringCells returns the screen columns that s occupies.
+//
+// Zero-width characters don't get a cell of their own.
+func (c *Condition) StringCells(s string) Cells {
+	var (
+		cells = make(Cells, 0, len(s))
+		line  int // Column at the start of the current line.
+	)
+	iter := c.clusters()
+	for i := 0; i < len(s); {
+		if s[i] == '\n' {
+			line = len(cells)
+		}
+		n, w := iter.at(s, i, len(cells)-line)
+		for j := 0; j < w; j++ {
+			cells = append(cells, Cell{Offset: i, Width: w, Continuation: j > 0})
+		}
+		i += n
+	}
+	return cells
+}
+
+// CellAt returns the cell at column col. The second return value is false if
+// col is out of range.
+func (cs Cells) CellAt(col int) (Cell, bool) {
+	if col < 0 || col >= len(cs) {
+		return Cell{}, false
+	}
+	return cs[col], true
+}
+
+// IsContinuation reports if col is a continuation cell of a wide character;
+// that is, a column where a cursor can't be placed.
+func (cs Cells) IsContinuation(col int) bool {
+	return col >= 0 && col < len(cs) && cs[col].Continuation
+}
+
+// Snap returns the column of the first cell of the character at col, for
+// moving a cursor to a valid position.
+func (cs Cells) Snap(col int) int {
+	for col > 0 && cs.IsContinuation(col) {
+		col--
+	}
+	return col
+}
+
+// StringCells returns the screen columns that s occupies.
+func StringCells(s string) Cells {
+	return DefaultCondition.StringCells(s)
+}
@@ -0,0 +1,47 @@
+package runewidth
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStringCells(t *testing.T) {
+	c := newCond(false)
+
+	have := c.StringCells("a世b́")
+	want := Cells{
+		{Offset: 0, Width: 1},
+		{Offset: 1, Width: 2},
+		{Offset: 1, Width: 2, Continuation: true},
+		{Offset: 4, Width: 1},
+	}
+	if !reflect.DeepEqual(have, want) {
+		t.Fatalf("\nhave: %+v\nwant: %+v", have, want)
+	}
+
+	tests := []struct {
+		col  int
+		ok   bool
+		cont bool
+		snap int
+	}{
+		{-1, false, false, -1},
+		{0, true, false, 0},
+		{1, true, false, 1},
+		{2, true, true, 1},
+		{3, true, false, 3},
+		{4, false, false, 4},
+	}
+	for _, tt := range tests {
+		cell, ok := have.CellAt(tt.col)
+		if ok != tt.ok || (ok && cell != want[tt.col]) {
+			t.Errorf("CellAt(%d) = (%+v, %t)", tt.col, cell, ok)
+		}
+		if cont := have.IsContinuation(tt.col); cont != tt.cont {
+			t.Errorf("IsContinuation(%d) = %t, want %t", tt.col, cont, tt.cont)
+		}
+		if snap := have.Snap(tt.col); snap != tt.snap {
+			t.Errorf("Snap(%d) = %d, want %d", tt.col, snap, tt.snap)
+		}
+	}
+}
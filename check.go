@@ -0,0 +1,113 @@
+package runewidth
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Mismatch is a character that doesn't have the expected width.
+type Mismatch struct {
+	Rune       rune
+	Have, Want int
+}
+
+// WidthError is returned if characters don't have the expected width.
+type WidthError struct {
+	Mismatches []Mismatch
+}
+
+func (e *WidthError) Error() string {
+	const max = 5
+	var b strings.Builder
+	fmt.Fprintf(&b, "runewidth: %d characters with unexpected width: ", len(e.Mismatches))
+	for i, m := range e.Mismatches {
+		if i == max {
+			fmt.Fprintf(&b, "; and %d more", len(e.Mismatches)-max)
+			break
+		}
+		if i > 0 {
+			b.WriteString("; ")
+		}
+		fmt.Fprintf(&b, "%U %q is %d, want %d", m.Rune, m.Rune, m.Have, m.Want)
+	}
+	return b.String()
+}
+
+// CheckRange checks if all characters from first to last (inclusive) are want
+// cells wide, returning a *WidthError if they're not.
+func (c *Condition) CheckRange(first, last rune, want int) error {
+	return c.checkTable(table{{first, last}}, want)
+}
+
+// CheckFullwidth checks if U+3000 IDEOGRAPHIC SPACE and the fullwidth forms
+// (U+FF01 to U+FF60 and U+FFE0 to U+FFE6) are 2 cells wide, returning a
+// *WidthError if they're not.
+//
+// These are always 2 cells wide in the default configuration, but this can be
+// used to verify that this is still the case with a custom configuration.
+func (c *Condition) CheckFullwidth() error {
+	return c.checkTable(fullwidth, 2)
+}
+
+func (c *Condition) checkTable(t table, want int) error {
+	var err *WidthError
+	for _, iv := range t {
+		for r := iv.first; r <= iv.last; r++ {
+			if have := c.RuneWidth(r); have != want {
+				if err == nil {
+					err = new(WidthError)
+				}
+				err.Mismatches = append(err.Mismatches, Mismatch{r, have, want})
+			}
+		}
+	}
+	if err == nil {
+		return nil
+	}
+	return err
+}
+
+// AssertWidths checks if all characters in want have the expected width,
+// returning a *WidthError with all characters that don't.
+//
+// This can be used at startup to verify that the characters an application
+// depends on, such as border characters or spinner frames, have the expected
+// width in the current configuration:
+//
+//	err := runewidth.AssertWidths(map[rune]int{'│': 1, '─': 1, '⠋': 1})
+func (c *Condition) AssertWidths(want map[rune]int) error {
+	var err *WidthError
+	for r, w := range want {
+		if have := c.RuneWidth(r); have != w {
+			if err == nil {
+				err = new(WidthError)
+			}
+			err.Mismatches = append(err.Mismatches, Mismatch{r, have, w})
+		}
+	}
+	if err == nil {
+		return nil
+	}
+	sort.Slice(err.Mismatches, func(i, j int) bool {
+		return err.Mismatches[i].Rune < err.Mismatches[j].Rune
+	})
+	return err
+}
+
+// AssertWidths checks if all characters in want have the expected width.
+func AssertWidths(want map[rune]int) error {
+	return DefaultCondition.AssertWidths(want)
+}
+
+// CheckRange checks if all characters from first to last (inclusive) are want
+// cells wide.
+func CheckRange(first, last rune, want int) error {
+	return DefaultCondition.CheckRange(first, last, want)
+}
+
+// CheckFullwidth checks if U+3000 IDEOGRAPHIC SPACE and the fullwidth forms
+// are 2 cells wide.
+func CheckFullwidth() error {
+	return DefaultCondition.CheckFullwidth()
+}
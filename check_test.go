@@ -0,0 +1,63 @@
+package runewidth
+
+import "testing"
+
+func TestCheckFullwidth(t *testing.T) {
+	for _, ea := range []bool{false, true} {
+		c := newCond(ea)
+		if err := c.CheckFullwidth(); err != nil {
+			t.Errorf("EastAsianWidth=%t: %s", ea, err)
+		}
+		c.CreateLUT()
+		if err := c.CheckFullwidth(); err != nil {
+			t.Errorf("EastAsianWidth=%t with LUT: %s", ea, err)
+		}
+	}
+}
+
+func TestCheckRange(t *testing.T) {
+	tests := []struct {
+		first, last rune
+		want        int
+		wantErr     string
+	}{
+		{'a', 'z', 1, ""},
+		{'世', '世', 2, ""},
+		{'a', 'c', 2, `runewidth: 3 characters with unexpected width: U+0061 'a' is 1, want 2; U+0062 'b' is 1, want 2; U+0063 'c' is 1, want 2`},
+		{'a', 'z', 2, `runewidth: 26 characters with unexpected width: U+0061 'a' is 1, want 2; U+0062 'b' is 1, want 2; U+0063 'c' is 1, want 2; U+0064 'd' is 1, want 2; U+0065 'e' is 1, want 2; and 21 more`},
+	}
+
+	c := newCond(false)
+	for _, tt := range tests {
+		var have string
+		if err := c.CheckRange(tt.first, tt.last, tt.want); err != nil {
+			have = err.Error()
+		}
+		if have != tt.wantErr {
+			t.Errorf("CheckRange(%U, %U, %d)\nhave: %s\nwant: %s", tt.first, tt.last, tt.want, have, tt.wantErr)
+		}
+	}
+}
+
+func TestAssertWidths(t *testing.T) {
+	tests := []struct {
+		in      map[rune]int
+		ea      bool
+		wantErr string
+	}{
+		{nil, false, ""},
+		{map[rune]int{'│': 1, '─': 1, '世': 2}, false, ""},
+		{map[rune]int{'│': 1, '─': 1, '世': 2}, true,
+			`runewidth: 2 characters with unexpected width: U+2500 '─' is 2, want 1; U+2502 '│' is 2, want 1`},
+	}
+
+	for _, tt := range tests {
+		var have string
+		if err := newCond(tt.ea).AssertWidths(tt.in); err != nil {
+			have = err.Error()
+		}
+		if have != tt.wantErr {
+			t.Errorf("AssertWidths(%v)\nhave: %s\nwant: %s", tt.in, have, tt.wantErr)
+		}
+	}
+}
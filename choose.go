@@ -0,0 +1,83 @@
+package runewidth
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Choose returns the first of variants that has a predictable width with the
+// settings in c, together with its width. This allows using a fallback for
+// labels such as "🚀 Launch" or "→ next" on terminals where they may not
+// align:
+//
+//	label, w := c.Choose("🚀 Launch", "> Launch")
+//
+// A variant doesn't have a predictable width if it contains ambiguous
+// characters and AmbiguousWidth isn't set, or characters that terminals
+// display with different widths (see IsContested) that aren't handled by a
+// setting: regional indicators with RegionalIndicators, emoji followed by
+// U+FE0F with VariationSelectors, and the Private Use Areas with
+// PrivateUseWidth or NerdFonts. ZWJ sequences are predictable only if ZWJ is
+// set. Characters with a width set with SetWidth or SetRangeWidth are always
+// predictable.
+//
+// The last variant is returned if none are predictable, so this should
+// usually be plain ASCII. An empty string is returned if there are no
+// variants.
+func (c *Condition) Choose(variants ...string) (string, int) {
+	if len(variants) == 0 {
+		return "", 0
+	}
+	for _, v := range variants[:len(variants)-1] {
+		if c.predictable(v) {
+			return v, c.StringWidth(v)
+		}
+	}
+	v := variants[len(variants)-1]
+	return v, c.StringWidth(v)
+}
+
+// predictable reports if s is displayed with the same width on all terminals
+// for the settings in c.
+func (c *Condition) predictable(s string) bool {
+	for i := 0; i < len(s); {
+		r, n := utf8.DecodeRuneInString(s[i:])
+		i += n
+		if len(c.overrides) > 0 && c.overrideAt(r) != nil {
+			continue
+		}
+		switch {
+		case r == 0x200D:
+			if c.ZWJ == ZWJNone {
+				return false
+			}
+		case isRegionalIndicator(r):
+			if !c.RegionalIndicators {
+				return false
+			}
+		case isPrivateUse(r):
+			if c.privateUseWidth() == 0 && !(c.NerdFonts && nerdFontWidth(r) > 0) {
+				return false
+			}
+		case inTable(r, emoji) && !inTable(r, doublewidth) && strings.HasPrefix(s[i:], "\ufe0f"):
+			if !c.VariationSelectors {
+				return false
+			}
+			i += len("\ufe0f")
+		case inTable(r, ambiguous):
+			if c.AmbiguousWidth != 1 && c.AmbiguousWidth != 2 {
+				return false
+			}
+		case !IsContested(r):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Choose returns the first of variants that has a predictable width, together
+// with its width.
+func Choose(variants ...string) (string, int) {
+	return DefaultCondition.Choose(variants...)
+}
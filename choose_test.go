@@ -0,0 +1,42 @@
+package runewidth
+
+import "testing"
+
+func TestChoose(t *testing.T) {
+	tests := []struct {
+		set      func(c *Condition)
+		variants []string
+		want     string
+		wantW    int
+	}{
+		{nil, nil, "", 0},
+		{nil, []string{"ok"}, "ok", 2},
+		{nil, []string{"🚀 Launch", "> Launch"}, "🚀 Launch", 9},
+		{nil, []string{"発射", "Launch"}, "発射", 4},
+		{nil, []string{"→ next", "-> next"}, "-> next", 7},
+		{func(c *Condition) { c.AmbiguousWidth = 1 }, []string{"→ next", "-> next"}, "→ next", 6},
+		{nil, []string{"❤ love", "<3 love"}, "<3 love", 7},
+		{nil, []string{"❤\ufe0f love", "<3 love"}, "<3 love", 7},
+		{func(c *Condition) { c.VariationSelectors = true }, []string{"❤\ufe0f love", "<3 love"}, "❤\ufe0f love", 7},
+		{nil, []string{"\U0001F1F3\U0001F1F1", "NL"}, "NL", 2},
+		{func(c *Condition) { c.RegionalIndicators = true }, []string{"\U0001F1F3\U0001F1F1", "NL"}, "\U0001F1F3\U0001F1F1", 2},
+		{nil, []string{"\ue0a0 main", "main"}, "main", 4},
+		{func(c *Condition) { c.NerdFonts = true }, []string{"\ue0a0 main", "main"}, "\ue0a0 main", 6},
+		{func(c *Condition) { c.NerdFonts = true }, []string{"\ue0a1\ue0c9", "x"}, "x", 1},
+		{func(c *Condition) { c.PrivateUseWidth = 1 }, []string{"\ue0c9", "x"}, "\ue0c9", 1},
+		{nil, []string{"\U0001F469\u200d\U0001F467", "family"}, "family", 6},
+		{func(c *Condition) { c.ZWJ = ZWJEmoji }, []string{"\U0001F469\u200d\U0001F467", "family"}, "\U0001F469\u200d\U0001F467", 2},
+		{func(c *Condition) { c.SetWidth('→', 2) }, []string{"→", "->"}, "→", 2},
+		{nil, []string{"\u00ad", "→", "-"}, "-", 1},
+	}
+	for _, tt := range tests {
+		c := newCond(false)
+		if tt.set != nil {
+			tt.set(c)
+		}
+		have, w := c.Choose(tt.variants...)
+		if have != tt.want || w != tt.wantW {
+			t.Errorf("Choose(%q) = %q, %d; want %q, %d", tt.variants, have, w, tt.want, tt.wantW)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package runewidth
+
+// Hint is a coarse classification of the width of a rune; see Classify.
+type Hint uint8
+
+// Hints for Classify.
+const (
+	HintLookup Hint = iota // Width depends on the tables and Condition.
+	HintNarrow             // Always 1 cell: printable ASCII.
+	HintWide               // Always 2 cells: CJK ideographs and Hangul syllables.
+)
+
+// Classify returns a coarse classification of r without looking it up in the
+// tables, so that performance-sensitive code can handle the common cases
+// itself and only call RuneWidth() for HintLookup.
+//
+// This is based on the built-in Unicode tables, and doesn't take options set
+// on a Condition into account.
+func Classify(r rune) Hint {
+	switch {
+	case r >= 0x20 && r < 0x7f:
+		return HintNarrow
+	case r < 0x3400:
+		return HintLookup
+	case r <= 0x4DBF, // CJK Unified Ideographs Extension A
+		r >= 0x4E00 && r <= 0x9FFF,   // CJK Unified Ideographs
+		r >= 0xAC00 && r <= 0xD7A3,   // Hangul Syllables
+		r >= 0x20000 && r <= 0x2FFFD, // Supplementary Ideographic Plane
+		r >= 0x30000 && r <= 0x3FFFD: // Tertiary Ideographic Plane
+		return HintWide
+	}
+	return HintLookup
+}
@@ -0,0 +1,26 @@
+package runewidth
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestClassify(t *testing.T) {
+	var n [3]int
+	for _, ea := range []bool{false, true} {
+		c := newCond(ea)
+		for r := rune(0); r <= utf8.MaxRune; r++ {
+			h := Classify(r)
+			n[h]++
+			switch w := c.RuneWidth(r); {
+			case h == HintNarrow && w != 1:
+				t.Errorf("Classify(%U) = HintNarrow, but width is %d (EastAsianWidth=%t)", r, w, ea)
+			case h == HintWide && w != 2:
+				t.Errorf("Classify(%U) = HintWide, but width is %d (EastAsianWidth=%t)", r, w, ea)
+			}
+		}
+	}
+	if n[HintNarrow] == 0 || n[HintWide] == 0 {
+		t.Errorf("%v", n)
+	}
+}
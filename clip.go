@@ -0,0 +1,129 @@
+package runewidth
+
+// Clip returns the part of s that's displayed in the columns from up to (but
+// not including) to.
+//
+// Wide characters that are partly inside the range are replaced with spaces,
+// so the result is always exactly to-from cells wide if s is wide enough. The
+// same is done for tabs that would be a different width in the result.
+// Zero-width characters are kept if the character they're attached to is
+// kept.
+func (c *Condition) Clip(s string, from, to int) string {
+	return c.clip(s, from, to, false)
+}
+
+// ClipANSI is like Clip, but skips ANSI escape sequences.
+//
+// Control characters are handled according to ANSIControls, and inline images
+// are treated as a single character with the width from ImageWidth.
+//
+// Escape sequences inside the range are copied as-is. The SGR sequences (colours
+// and other attributes) that are active at the start of the range are
+// re-emitted at the start, and a reset is added at the end if any attributes
+// are still active.
+func (c *Condition) ClipANSI(s string, from, to int) string {
+	return c.clip(s, from, to, true)
+}
+
+func (c *Condition) clip(s string, from, to int, ansi bool) string {
+	var (
+		b       = make([]byte, 0, len(s))
+		col     int
+		line    int // Column at the start of the current line.
+		keep    bool
+		started bool
+		sgr     sgrState
+	)
+	start := func() {
+		if !started {
+			started = true
+			for _, seq := range sgr {
+				b = append(b, seq...)
+			}
+		}
+	}
+	iter := c.clusters()
+	for i := 0; i < len(s); {
+		var size, cw int
+		if ansi {
+			if n := escapeLen(s[i:]); n > 0 {
+				seq := s[i : i+n]
+				if c.ImageWidth != nil && isImage(seq) {
+					size, cw = n, c.ImageWidth(seq)
+				}
+				if cw <= 0 {
+					if isSGR(seq) {
+						sgr.add(seq)
+					}
+					if started {
+						b = append(b, seq...)
+					}
+					i += n
+					continue
+				}
+			} else if c.ANSIControls == ControlIgnore && isTextControl(s[i]) {
+				i++
+				continue
+			}
+		}
+
+		if size == 0 {
+			if s[i] == '\n' {
+				line = col
+			}
+			size, cw = iter.at(s, i, col-line)
+		}
+		if cw == 0 {
+			if keep {
+				b = append(b, s[i:i+size]...)
+			}
+			i += size
+			continue
+		}
+		if col >= to {
+			break
+		}
+
+		end := col + cw
+		keep = false
+		switch {
+		case col >= from && end <= to:
+			start()
+			outCol := col - from
+			if line > from {
+				outCol = col - line
+			}
+			if s[i] == '\t' && c.tabStop('\t', outCol, 0) != cw {
+				// Tab stops are at different columns in the result.
+				b = appendSpaces(b, cw)
+			} else {
+				b = append(b, s[i:i+size]...)
+			}
+			keep = true
+		case end > from:
+			start()
+			for x := col; x < end; x++ {
+				if x >= from && x < to {
+					b = append(b, ' ')
+				}
+			}
+		}
+		col = end
+		i += size
+	}
+	if ansi && started && len(sgr) > 0 {
+		b = append(b, sgrReset...)
+	}
+	return string(b)
+}
+
+// Clip returns the part of s that's displayed in the columns from up to (but
+// not including) to.
+func Clip(s string, from, to int) string {
+	return DefaultCondition.Clip(s, from, to)
+}
+
+// ClipANSI is like Clip, but skips ANSI escape sequences.
+func ClipANSI(s string, from, to int) string {
+	return DefaultCondition.ClipANSI(s, from, to)
+}
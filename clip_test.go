@@ -0,0 +1,190 @@
+package runewidth
+
+import (
+	"testing"
+)
+
+func TestClip(t *testing.T) {
+	tests := []struct {
+		in       string
+		from, to int
+		want     string
+	}{
+		{"", 0, 5, ""},
+		{"abcdef", 0, 3, "abc"},
+		{"abcdef", 2, 4, "cd"},
+		{"abcdef", 4, 10, "ef"},
+		{"abcdef", 8, 10, ""},
+		{"あいう", 0, 4, "あい"},
+		{"あいう", 1, 4, " い"},
+		{"あいう", 1, 5, " い "},
+		{"あいう", 2, 3, " "},
+		{"ab̀cd", 1, 2, "b̀"},
+		{"ab̀cd", 2, 3, "c"},
+	}
+
+	c := newCond(false)
+	for _, tt := range tests {
+		if have := c.Clip(tt.in, tt.from, tt.to); have != tt.want {
+			t.Errorf("Clip(%q, %d, %d) = %q, want %q", tt.in, tt.from, tt.to, have, tt.want)
+		}
+	}
+}
+
+func TestClipANSI(t *testing.T) {
+	tests := []struct {
+		in       string
+		from, to int
+		want     string
+	}{
+		{"abcdef", 2, 4, "cd"},
+		{"\x1b[31mabcdef\x1b[0m", 2, 4, "\x1b[31mcd\x1b[0m"},
+		{"\x1b[31mab\x1b[0mcdef", 2, 4, "cd"},
+		{"ab\x1b[1mcd\x1b[0mef", 2, 4, "\x1b[1mcd\x1b[0m"},
+		{"ab\x1b[1mcd\x1b[0mef", 1, 5, "b\x1b[1mcd\x1b[0me"},
+		{"\x1b[1ma\x1b[32mbc\x1b[mdef", 1, 4, "\x1b[1m\x1b[32mbc\x1b[md"},
+		{"\x1b[1ma\x1b[0;32mbcdef", 1, 3, "\x1b[0;32mbc\x1b[0m"},
+		{"\x1b]8;;http://example.com\x1b\\link\x1b]8;;\x1b\\", 0, 2, "li"},
+		{"\x1b[31mあいう", 1, 4, "\x1b[31m い\x1b[0m"},
+		{"ab\x1bPq#0;2;0;0;0#1~~@@vv@@~~@@~~$-\x1b\\cd", 1, 3, "b\x1bPq#0;2;0;0;0#1~~@@vv@@~~@@~~$-\x1b\\c"},
+	}
+
+	c := newCond(false)
+	for _, tt := range tests {
+		if have := c.ClipANSI(tt.in, tt.from, tt.to); have != tt.want {
+			t.Errorf("ClipANSI(%q, %d, %d)\nhave: %q\nwant: %q", tt.in, tt.from, tt.to, have, tt.want)
+		}
+	}
+}
+
+func TestEscapeLen(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"", 0},
+		{"a", 0},
+		{"\x1b", 0},
+		{"\x1b[m", 3},
+		{"\x1b[0;31mxx", 7},
+		{"\x1b[?25h", 6},
+		{"\x1b[31", 4},
+		{"\x1b]0;title\x07xx", 10},
+		{"\x1b]0;title\x1b\\xx", 11},
+		{"\x1b]0;title", 9},
+		{"\x1b7x", 2},
+		{"\x1b(Bx", 3},
+		{"\x1b_Ga=T;AAAA\x1b\\x", 13},
+		{"\x1b_Ga=T;AAAA\x07x", 13},
+		{"\x1b^private\x1b\\", 11},
+		{"\x1bPq#0;2;0;0;0#1!6~-\x1b\\x", 21},
+	}
+	for _, tt := range tests {
+		if have := escapeLen(tt.in); have != tt.want {
+			t.Errorf("escapeLen(%q) = %d, want %d", tt.in, have, tt.want)
+		}
+	}
+}
+
+func TestClipANSIControls(t *testing.T) {
+	tests := []struct {
+		in     string
+		policy ControlPolicy
+		want   string
+	}{
+		{"ab\acd\bef", ControlZero, "b\acd\be"},
+		{"ab\acd\bef", ControlIgnore, "bcde"},
+		{"ab\acd\bef", ControlReject, "b\acd\be"},
+		{"a\x1b]0;t\x07bc\tdef", ControlIgnore, "bc  "},
+	}
+
+	for _, tt := range tests {
+		c := newCond(false)
+		c.ANSIControls = tt.policy
+		if have := c.ClipANSI(tt.in, 1, 5); have != tt.want {
+			t.Errorf("ClipANSI(%q) with %d\nhave: %q\nwant: %q", tt.in, tt.policy, have, tt.want)
+		}
+	}
+}
+
+func TestCheckANSI(t *testing.T) {
+	tests := []struct {
+		in     string
+		policy ControlPolicy
+		want   string
+	}{
+		{"ab\acd", ControlZero, ""},
+		{"ab\acd", ControlIgnore, ""},
+		{"ab\acd", ControlReject, "runewidth: control character 0x07 at offset 2"},
+		{"\x1b]0;title\x07a\tb\r\n", ControlReject, ""},
+		{"\x1b[31ma\x0cb", ControlReject, "runewidth: control character 0x0c at offset 6"},
+	}
+
+	for _, tt := range tests {
+		c := newCond(false)
+		c.ANSIControls = tt.policy
+		var have string
+		if err := c.CheckANSI(tt.in); err != nil {
+			have = err.Error()
+		}
+		if have != tt.want {
+			t.Errorf("CheckANSI(%q) with %d\nhave: %q\nwant: %q", tt.in, tt.policy, have, tt.want)
+		}
+	}
+}
+
+func TestClipANSIImage(t *testing.T) {
+	var (
+		kitty = "\x1b_Ga=T,f=100,c=3;aGVsbG8=\x1b\\"
+		iterm = "\x1b]1337;File=name=eC5wbmc=;width=2;inline=1:aGVsbG8=\x07"
+	)
+	tests := []struct {
+		in       string
+		from, to int
+		images   bool
+		want     string
+	}{
+		{"a" + kitty + "bcdef", 1, 3, false, "bc"},
+		{"a" + kitty + "bcdef", 1, 3, true, "  "},
+		{"a" + kitty + "bcdef", 1, 5, true, kitty + "b"},
+		{"a" + iterm + "bcdef", 0, 4, true, "a" + iterm + "b"},
+		{"a" + iterm + "bcdef", 0, 4, false, "a" + iterm + "bcd"},
+		{"a\x1b_Gi=31;OK\x1b\\bc", 0, 2, true, "a\x1b_Gi=31;OK\x1b\\b"},
+	}
+
+	for _, tt := range tests {
+		c := newCond(false)
+		if tt.images {
+			c.ImageWidth = func(seq string) int {
+				n, _ := ImageCells(seq)
+				return n
+			}
+		}
+		if have := c.ClipANSI(tt.in, tt.from, tt.to); have != tt.want {
+			t.Errorf("ClipANSI(%q, %d, %d)\nhave: %q\nwant: %q", tt.in, tt.from, tt.to, have, tt.want)
+		}
+	}
+}
+
+func TestImageCells(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+		ok   bool
+	}{
+		{"", 0, false},
+		{"\x1b[31m", 0, false},
+		{"\x1b_Ga=T,c=10,r=2;AAAA\x1b\\", 10, true},
+		{"\x1b_Ga=T,r=2;c=10\x1b\\", 0, false},
+		{"\x1b_Ga=T,c=x\x1b\\", 0, false},
+		{"\x1b]1337;File=width=5;inline=1:AAAA\x07", 5, true},
+		{"\x1b]1337;File=width=50px;inline=1:AAAA\x07", 0, false},
+		{"\x1b]1337;File=inline=1:width=5\x07", 0, false},
+	}
+	for _, tt := range tests {
+		have, ok := ImageCells(tt.in)
+		if have != tt.want || ok != tt.ok {
+			t.Errorf("ImageCells(%q) = (%d, %t), want (%d, %t)", tt.in, have, ok, tt.want, tt.ok)
+		}
+	}
+}
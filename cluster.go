@@ -0,0 +1,160 @@
+package runewidth
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// ZWJPolicy sets how U+200D ZERO WIDTH JOINER is handled by the string
+// functions. The joiner itself is always zero width.
+type ZWJPolicy uint8
+
+const (
+	// ZWJNone doesn't join anything: the character after a joiner is a
+	// separate character with its own width.
+	ZWJNone ZWJPolicy = iota
+
+	// ZWJEmoji makes an emoji ZWJ sequence such as 👩‍👩‍👧 a single character
+	// with the width of the first emoji. A joiner between other characters
+	// is handled as with ZWJNone.
+	ZWJEmoji
+
+	// ZWJJoin is like ZWJEmoji, but also keeps the characters on both sides
+	// of a joiner between other characters together, such as Indic conjuncts,
+	// so functions like Truncate and Wrap never split them. The width is the
+	// sum of the widths of the characters.
+	ZWJJoin
+)
+
+// cluster returns the length in bytes and the width of the first character in
+// s, including any zero-width characters that follow it such as combining
+// marks.
+//
+// This isn't a full implementation of grapheme clusters, but it ensures that
+// functions such as Truncate and Wrap don't separate a character from its
+// combining marks, and allows for some rules that depend on the sequence of
+// characters.
+func (c *Condition) cluster(s string) (n, w int) {
+	n, w, _ = c.clusterNext(s, -1)
+	return n, w
+}
+
+// clusterNext is like cluster, but takes the width of the first rune in s if
+// it's already known (-1 if it's not), and also returns the width of the rune
+// at s[n:] if it was looked up to see if it extends the cluster (-1 if it
+// wasn't).
+func (c *Condition) clusterNext(s string, first int) (n, w, next int) {
+	r, n := utf8.DecodeRuneInString(s)
+	w, next = first, -1
+	if w < 0 {
+		w = c.RuneWidth(r)
+	}
+	if isControl(r) {
+		return n, w, next
+	}
+	if c.Marks != 0 && c.visibleMark(r) {
+		return n, 1, next
+	}
+	if c.VariationSelectors && (r == 0xFE0E || r == 0xFE0F) {
+		w = 0
+	}
+	base := r
+	if c.RegionalIndicators && isRegionalIndicator(r) && n < len(s) {
+		if r2, size := utf8.DecodeRuneInString(s[n:]); isRegionalIndicator(r2) {
+			n, w = n+size, 2
+		}
+	}
+	for n < len(s) {
+		r, size := utf8.DecodeRuneInString(s[n:])
+		if r == 0x200D && c.ZWJ != ZWJNone && n+size < len(s) {
+			next, nsize := utf8.DecodeRuneInString(s[n+size:])
+			switch {
+			case c.ZWJ >= ZWJEmoji && inTable(base, emoji) && inTable(next, emoji):
+				n, base = n+size+nsize, next
+				continue
+			case c.ZWJ == ZWJJoin && !inTable(base, emoji) && !inTable(next, emoji) &&
+				!isControl(next) && next != 0x2028 && next != 0x2029:
+				n, base, w = n+size+nsize, next, w+c.RuneWidth(next)
+				continue
+			}
+		}
+		if c.VariationSelectors && (r == 0xFE0E || r == 0xFE0F) {
+			if w > 0 && hasVariation(base) {
+				w = int(r-0xFE0E) + 1
+			}
+			n += size
+			continue
+		}
+		rw := c.RuneWidth(r)
+		if !c.extends(r, rw) {
+			next = rw
+			break
+		}
+		if c.WideEnclosing && w == 1 && unicode.Is(unicode.Me, r) {
+			w = 2
+		}
+		n += size
+	}
+	return n, w, next
+}
+
+// extends reports if r, which is w cells wide, is part of the cluster before
+// it.
+func (c *Condition) extends(r rune, w int) bool {
+	return w == 0 && !isControl(r) && r != 0x2028 && r != 0x2029 &&
+		(c.Marks == 0 || !c.visibleMark(r))
+}
+
+// clusterIter finds the clusters in a string like clusterAt, but passes the
+// width of the rune after a cluster that clusterNext looked up on to the next
+// cluster, so that every rune is looked up only once.
+type clusterIter struct {
+	c    *Condition
+	off  int // Byte offset of the rune after the previous cluster.
+	next int // Width of the rune at off, or -1 if it's not known.
+}
+
+func (c *Condition) clusters() clusterIter {
+	return clusterIter{c: c, next: -1}
+}
+
+// at returns the length and width of the cluster at s[i:], which is at column
+// col. s must be the same string on every call.
+func (it *clusterIter) at(s string, i, col int) (n, w int) {
+	if s[i] == '\t' {
+		it.off, it.next = -1, -1
+		return 1, it.c.tabStop('\t', col, 0)
+	}
+	first := -1
+	if i == it.off {
+		first = it.next
+	}
+	n, w, it.next = it.c.clusterNext(s[i:], first)
+	it.off = i + n
+	return n, w
+}
+
+// clusterEnds reports if the cluster before s can't be extended by text that's
+// appended to s. cluster looks at most two runes ahead, and never past a
+// control character.
+func clusterEnds(s string) bool {
+	if len(s) > 0 && (s[0] < 0x20 || s[0] == 0x7f) {
+		return true
+	}
+	if !utf8.FullRuneInString(s) {
+		return false
+	}
+	_, n := utf8.DecodeRuneInString(s)
+	return utf8.FullRuneInString(s[n:])
+}
+
+// isRegionalIndicator reports if r is one of the regional indicator symbols
+// that are used in pairs for flags.
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1F1E6 && r <= 0x1F1FF
+}
+
+// isControl reports if r is a C0 or C1 control character.
+func isControl(r rune) bool {
+	return r < 0x20 || (r >= 0x7f && r <= 0x9f)
+}
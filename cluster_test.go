@@ -0,0 +1,89 @@
+package runewidth
+
+import "testing"
+
+func TestRegionalIndicators(t *testing.T) {
+	tests := []struct {
+		in       string
+		w        int
+		off, on  string
+		wrapOn   string
+		wantWide int
+	}{
+		{"🇳🇱", 2, "🇳🇱", "🇳🇱", "🇳🇱", 2},
+		{"🇳🇱🇯🇵", 4, "🇳🇱🇯", "🇳🇱", "🇳🇱\n🇯🇵", 4},
+		{"ab🇳🇱c", 5, "ab🇳", "ab", "ab\n🇳🇱\nc", 5},
+		{"🇳🇱🇯", 3, "🇳🇱🇯", "🇳🇱🇯", "🇳🇱\n🇯", 3},
+		{"🇳", 1, "🇳", "🇳", "🇳", 1},
+	}
+
+	for _, tt := range tests {
+		c := newCond(false)
+		if have := c.StringWidth(tt.in); have != tt.w {
+			t.Errorf("StringWidth(%q) = %d, want %d", tt.in, have, tt.w)
+		}
+		if have := c.Truncate(tt.in, 3, ""); have != tt.off {
+			t.Errorf("Truncate(%q, 3) = %q, want %q", tt.in, have, tt.off)
+		}
+
+		c.RegionalIndicators = true
+		if have := c.StringWidth(tt.in); have != tt.wantWide {
+			t.Errorf("StringWidth(%q) = %d, want %d (RegionalIndicators)", tt.in, have, tt.wantWide)
+		}
+		if have := c.Truncate(tt.in, 3, ""); have != tt.on {
+			t.Errorf("Truncate(%q, 3) = %q, want %q (RegionalIndicators)", tt.in, have, tt.on)
+		}
+		if have := c.Wrap(tt.in, 2); have != tt.wrapOn {
+			t.Errorf("Wrap(%q, 2) = %q, want %q (RegionalIndicators)", tt.in, have, tt.wrapOn)
+		}
+	}
+}
+
+func TestZWJ(t *testing.T) {
+	tests := []struct {
+		in     string
+		pol    ZWJPolicy
+		w      int
+		trunc  string // Truncated to 2 cells.
+		nchars int
+	}{
+		// Indic; the virama is not zero width in the tables.
+		{"क्‍ष", ZWJNone, 3, "क्‍", 3},
+		{"क्‍ष", ZWJEmoji, 3, "क्‍", 3},
+		{"aक‍ष", ZWJEmoji, 3, "aक‍", 3},
+		{"aक‍ष", ZWJJoin, 3, "a", 2},
+		{"क‍षa", ZWJJoin, 3, "क‍ष", 2},
+		{"ര്‍a", ZWJJoin, 3, "ര", 2},
+
+		// Emoji sequences.
+		{"👩‍👩‍👧", ZWJNone, 6, "👩‍", 3},
+		{"👩‍👩‍👧", ZWJEmoji, 2, "👩‍👩‍👧", 1},
+		{"👩‍👩‍👧", ZWJJoin, 2, "👩‍👩‍👧", 1},
+
+		// Mixed; never joined.
+		{"a‍👩", ZWJEmoji, 3, "a‍", 2},
+		{"a‍👩", ZWJJoin, 3, "a‍", 2},
+		{"👩‍a", ZWJJoin, 3, "👩‍", 2},
+		{"👩‍", ZWJEmoji, 2, "👩‍", 1},
+		{"a‍\nb", ZWJJoin, 2, "a‍\nb", 3},
+	}
+
+	for _, tt := range tests {
+		c := newCond(false)
+		c.ZWJ = tt.pol
+		if have := c.StringWidth(tt.in); have != tt.w {
+			t.Errorf("StringWidth(%q) with %d = %d, want %d", tt.in, tt.pol, have, tt.w)
+		}
+		if have := c.Truncate(tt.in, 2, ""); have != tt.trunc {
+			t.Errorf("Truncate(%q, 2) with %d = %q, want %q", tt.in, tt.pol, have, tt.trunc)
+		}
+		n := 0
+		for s := tt.in; s != ""; n++ {
+			l, _ := c.cluster(s)
+			s = s[l:]
+		}
+		if n != tt.nchars {
+			t.Errorf("%q with %d: %d characters, want %d", tt.in, tt.pol, n, tt.nchars)
+		}
+	}
+}
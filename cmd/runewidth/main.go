@@ -0,0 +1,86 @@
+// Command runewidth wraps text to the display width of the terminal.
+//
+// Usage:
+//
+//	runewidth wrap [-w width] [-words] [-terminal] < file
+//
+// With -terminal the width is read from the terminal on stdout, and is updated
+// when the terminal is resized (on SIGWINCH), so that lines read after a resize
+// are wrapped to the new width.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"zgo.at/runewidth"
+)
+
+func fatal(err error) {
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "runewidth:", err.Error())
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: runewidth wrap [-w width] [-words] [-terminal] < file")
+	os.Exit(2)
+}
+
+func main() {
+	if len(os.Args) < 2 || os.Args[1] != "wrap" {
+		usage()
+	}
+
+	f := flag.NewFlagSet("wrap", flag.ExitOnError)
+	var (
+		w        = f.Int("w", 80, "wrap at this width")
+		words    = f.Bool("words", false, "break lines at white space")
+		terminal = f.Bool("terminal", false, "wrap at the width of the terminal, and update it when resized")
+	)
+	fatal(f.Parse(os.Args[2:]))
+	if f.NArg() > 0 {
+		usage()
+	}
+
+	width := int32(*w)
+	if *terminal {
+		fd := int(os.Stdout.Fd())
+		cols, err := termWidth(fd)
+		fatal(err)
+		width = int32(cols)
+		watchWidth(fd, &width)
+	}
+	fatal(wrap(os.Stdout, os.Stdin, &width, runewidth.WrapOpts{Words: *words}))
+}
+
+// wrap wraps every line from r to the width in w, which may be changed while
+// reading. Lines are written as soon as they're read.
+func wrap(out io.Writer, r io.Reader, w *int32, opts runewidth.WrapOpts) error {
+	in := bufio.NewReader(r)
+	for {
+		line, err := in.ReadString('\n')
+		if line != "" {
+			l := strings.TrimSuffix(line, "\n")
+			l = runewidth.WrapWith(l, int(atomic.LoadInt32(w)), opts)
+			if strings.HasSuffix(line, "\n") {
+				l += "\n"
+			}
+			if _, werr := io.WriteString(out, l); werr != nil {
+				return werr
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd
+
+package main
+
+import "errors"
+
+func termWidth(fd int) (int, error) {
+	return 0, errors.New("-terminal is not supported on this system")
+}
+
+func watchWidth(fd int, w *int32) {}
@@ -0,0 +1,42 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd
+
+package main
+
+import (
+	"errors"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+	"unsafe"
+)
+
+type winsize struct {
+	row, col, xpixel, ypixel uint16
+}
+
+// termWidth gets the number of columns of the terminal on fd.
+func termWidth(fd int) (int, error) {
+	var ws winsize
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), uintptr(syscall.TIOCGWINSZ), uintptr(unsafe.Pointer(&ws)))
+	if errno != 0 {
+		return 0, errno
+	}
+	if ws.col == 0 {
+		return 0, errors.New("terminal has no width")
+	}
+	return int(ws.col), nil
+}
+
+// watchWidth updates w with the width of the terminal on fd when it's resized.
+func watchWidth(fd int, w *int32) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGWINCH)
+	go func() {
+		for range ch {
+			if cols, err := termWidth(fd); err == nil {
+				atomic.StoreInt32(w, int32(cols))
+			}
+		}
+	}()
+}
@@ -0,0 +1,66 @@
+package runewidth
+
+import "sort"
+
+// ColumnIndex maps between byte offsets and columns in a string, so that text
+// editors and pagers can move a cursor without measuring the string again.
+// Lookups are O(log n) in the number of characters; see StringCells for a
+// lookup by column in O(1) that uses more memory.
+type ColumnIndex struct {
+	offsets []int // Byte offset of every character, and len(s) at the end.
+	cols    []int // Column of every character, and the width of s at the end.
+}
+
+// NewColumnIndex creates a ColumnIndex for s, which should be a single line.
+//
+// Like in StringCells a character includes the zero-width characters that
+// follow it, such as combining marks.
+func (c *Condition) NewColumnIndex(s string) *ColumnIndex {
+	x := &ColumnIndex{
+		offsets: make([]int, 0, len(s)+1),
+		cols:    make([]int, 0, len(s)+1),
+	}
+	var col int
+	iter := c.clusters()
+	for i := 0; i < len(s); {
+		n, w := iter.at(s, i, col)
+		x.offsets, x.cols = append(x.offsets, i), append(x.cols, col)
+		col += w
+		i += n
+	}
+	x.offsets, x.cols = append(x.offsets, len(s)), append(x.cols, col)
+	return x
+}
+
+// Width returns the width of the string.
+func (x *ColumnIndex) Width() int { return x.cols[len(x.cols)-1] }
+
+// ColumnAt returns the column of the character at byte offset off, which may
+// be in the middle of a character. The width of the string is returned if off
+// is past the end.
+func (x *ColumnIndex) ColumnAt(off int) int {
+	if off < 0 {
+		off = 0
+	}
+	return x.cols[sort.SearchInts(x.offsets, off+1)-1]
+}
+
+// OffsetAt returns the byte offset of the character at column col. If col is
+// a continuation cell of a wide character then the offset of that character is
+// returned, and if there are zero-width characters at col then the offset of
+// the first one. The length of the string is returned if col is past the end.
+func (x *ColumnIndex) OffsetAt(col int) int {
+	if col < 0 {
+		col = 0
+	}
+	i := sort.SearchInts(x.cols, col)
+	if i == len(x.cols) || x.cols[i] > col {
+		i--
+	}
+	return x.offsets[i]
+}
+
+// NewColumnIndex creates a ColumnIndex for s, which should be a single line.
+func NewColumnIndex(s string) *ColumnIndex {
+	return DefaultCondition.NewColumnIndex(s)
+}
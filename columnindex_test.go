@@ -0,0 +1,78 @@
+package runewidth
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestColumnIndex(t *testing.T) {
+	tests := []string{
+		"",
+		"abc",
+		"つのだ☆HIRO",
+		"a\u0301b\u0302\u0303c",
+		"a\tb\tc",
+		"\x00\x01a",
+		"a\x00",
+		"\U0001F469\u200d\U0001F467 x",
+		"\xff\xe3\x81a",
+	}
+	for _, s := range tests {
+		t.Run(fmt.Sprintf("%q", s), func(t *testing.T) {
+			c := newCond(false)
+			c.ZWJ = ZWJEmoji
+			x := c.NewColumnIndex(s)
+			if have, want := x.Width(), c.StringWidth(s); have != want {
+				t.Errorf("Width = %d, want %d", have, want)
+			}
+
+			// Compare to measuring every character.
+			type char struct{ off, col int }
+			var chars []char
+			for i, col := 0, 0; i < len(s); {
+				n, w := c.clusterAt(s[i:], col)
+				chars = append(chars, char{i, col})
+				i, col = i+n, col+w
+			}
+			chars = append(chars, char{len(s), x.Width()})
+			for off := -1; off <= len(s)+1; off++ {
+				var want int
+				for i := len(chars) - 1; i >= 0; i-- {
+					if chars[i].off <= off || i == 0 {
+						want = chars[i].col
+						break
+					}
+				}
+				if have := x.ColumnAt(off); have != want {
+					t.Errorf("ColumnAt(%d) = %d, want %d", off, have, want)
+				}
+			}
+			for col := -1; col <= x.Width()+1; col++ {
+				want := len(s)
+				for i := len(chars) - 1; i >= 0; i-- {
+					if chars[i].col <= col || i == 0 {
+						want = chars[i].off
+						for i > 0 && chars[i-1].col == chars[i].col {
+							i--
+							want = chars[i].off
+						}
+						break
+					}
+				}
+				if col > x.Width() {
+					want = len(s)
+				}
+				if have := x.OffsetAt(col); have != want {
+					t.Errorf("OffsetAt(%d) = %d, want %d", col, have, want)
+				}
+			}
+		})
+	}
+
+	x := NewColumnIndex("\x00a世b")
+	for _, tt := range []struct{ col, off int }{{0, 0}, {1, 2}, {2, 2}, {3, 5}, {4, 6}} {
+		if have := x.OffsetAt(tt.col); have != tt.off {
+			t.Errorf("OffsetAt(%d) = %d, want %d", tt.col, have, tt.off)
+		}
+	}
+}
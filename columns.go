@@ -0,0 +1,82 @@
+package runewidth
+
+import "strings"
+
+// ColumnLayout calculates how to lay out items in columns within width cells,
+// like ls or shell completion menus do.
+//
+// Items are ordered in columns first (top to bottom, then left to right), with
+// gutter cells between the columns. It returns the number of rows and the
+// width of every column. There is always at least one column, even if the
+// widest item doesn't fit.
+func (c *Condition) ColumnLayout(items []string, width, gutter int) (rows int, widths []int) {
+	if len(items) == 0 {
+		return 0, nil
+	}
+	iw := make([]int, len(items))
+	for i, s := range items {
+		iw[i] = c.StringWidth(s)
+	}
+
+	for rows = 1; rows < len(items); rows++ {
+		if widths = columnWidths(iw, rows); sumColumns(widths, gutter) <= width {
+			return rows, widths
+		}
+	}
+	return len(items), columnWidths(iw, len(items))
+}
+
+// Columns lays out items in columns within width cells; see ColumnLayout.
+//
+// Items are padded to the width of their column, except for the last item on
+// a line.
+func (c *Condition) Columns(items []string, width, gutter int) string {
+	rows, widths := c.ColumnLayout(items, width, gutter)
+	var b strings.Builder
+	for r := 0; r < rows; r++ {
+		for col := range widths {
+			i := col*rows + r
+			if i >= len(items) {
+				break
+			}
+			if col > 0 {
+				b.WriteString(strings.Repeat(" ", gutter))
+			}
+			if i+rows >= len(items) { // Nothing to the right.
+				b.WriteString(items[i])
+				break
+			}
+			b.WriteString(c.FillRight(items[i], widths[col]))
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+func columnWidths(iw []int, rows int) []int {
+	widths := make([]int, (len(iw)+rows-1)/rows)
+	for i, w := range iw {
+		if col := i / rows; w > widths[col] {
+			widths[col] = w
+		}
+	}
+	return widths
+}
+
+func sumColumns(widths []int, gutter int) int {
+	t := gutter * (len(widths) - 1)
+	for _, w := range widths {
+		t += w
+	}
+	return t
+}
+
+// ColumnLayout calculates how to lay out items in columns within width cells.
+func ColumnLayout(items []string, width, gutter int) (rows int, widths []int) {
+	return DefaultCondition.ColumnLayout(items, width, gutter)
+}
+
+// Columns lays out items in columns within width cells.
+func Columns(items []string, width, gutter int) string {
+	return DefaultCondition.Columns(items, width, gutter)
+}
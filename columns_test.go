@@ -0,0 +1,51 @@
+package runewidth
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestColumnLayout(t *testing.T) {
+	tests := []struct {
+		in     []string
+		width  int
+		rows   int
+		widths []int
+	}{
+		{nil, 80, 0, nil},
+		{[]string{"a", "b", "c"}, 80, 1, []int{1, 1, 1}},
+		{[]string{"a", "b", "c"}, 4, 2, []int{1, 1}},
+		{[]string{"a", "b", "c"}, 0, 3, []int{1}},
+		{[]string{"abcdef", "x"}, 3, 2, []int{6}},
+		{[]string{"東京", "a", "大阪", "bb", "c"}, 9, 3, []int{4, 2}},
+	}
+
+	c := newCond(false)
+	for _, tt := range tests {
+		rows, widths := c.ColumnLayout(tt.in, tt.width, 2)
+		if rows != tt.rows || !reflect.DeepEqual(widths, tt.widths) {
+			t.Errorf("ColumnLayout(%q, %d) = (%d, %v), want (%d, %v)",
+				tt.in, tt.width, rows, widths, tt.rows, tt.widths)
+		}
+	}
+}
+
+func TestColumns(t *testing.T) {
+	tests := []struct {
+		in    []string
+		width int
+		want  string
+	}{
+		{nil, 80, ""},
+		{[]string{"a", "b", "c"}, 80, "a  b  c\n"},
+		{[]string{"a", "b", "c"}, 4, "a  c\nb\n"},
+		{[]string{"東京", "a", "大阪", "bb", "c"}, 9, "東京  bb\na     c\n大阪\n"},
+	}
+
+	c := newCond(false)
+	for _, tt := range tests {
+		if have := c.Columns(tt.in, tt.width, 2); have != tt.want {
+			t.Errorf("Columns(%q, %d)\nhave: %q\nwant: %q", tt.in, tt.width, have, tt.want)
+		}
+	}
+}
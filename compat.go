@@ -0,0 +1,73 @@
+package runewidth
+
+// Compat makes a Condition return the same widths as the wcwidth() function
+// from a C library.
+type Compat uint8
+
+const (
+	// CompatNone uses the widths from this package.
+	CompatNone Compat = iota
+
+	// CompatGlibc uses the widths from glibc 2.36 in a UTF-8 locale (Unicode
+	// 14.0). Combining marks and format characters are zero-width, and
+	// unassigned code points are non-printable.
+	CompatGlibc
+
+	// CompatMusl uses the widths from musl's wcwidth() with the Unicode 14.0
+	// tables. Combining marks and format characters are zero-width, and
+	// unassigned code points are 1 cell wide.
+	CompatMusl
+)
+
+// compatTables returns the tables for c.Compat: zero-width, wide, and
+// non-printable characters; everything else is 1 cell wide.
+func (c *Condition) compatTables() (zero, wide, np table, ok bool) {
+	switch c.Compat {
+	case CompatGlibc:
+		return glibcZero, glibcWide, glibcNonprint, true
+	case CompatMusl:
+		return muslZero, muslWide, muslNonprint, true
+	}
+	return nil, nil, nil, false
+}
+
+// compatWidth returns the width of r for c.Compat, which must be set.
+func (c *Condition) compatWidth(r rune) int {
+	zero, wide, np, _ := c.compatTables()
+	switch {
+	case inTable(r, zero):
+		return 0
+	case inTable(r, wide):
+		return 2
+	case inTable(r, np):
+		return 0
+	}
+	return 1
+}
+
+// Wcwidth returns the width of r like the C wcwidth() function: -1 for
+// control characters and other non-printable characters, and the same as
+// RuneWidth() for everything else.
+//
+// The results are identical to the C library's wcwidth() if Compat is set.
+func (c *Condition) Wcwidth(r rune) int {
+	if r < 0 || r > 0x10FFFF {
+		return -1
+	}
+	if _, _, np, ok := c.compatTables(); ok {
+		if inTable(r, np) {
+			return -1
+		}
+		return c.compatWidth(r)
+	}
+	if r != 0 && isControl(r) {
+		return -1
+	}
+	return c.RuneWidth(r)
+}
+
+// Wcwidth returns the width of r like the C wcwidth() function; see
+// Condition.Wcwidth.
+func Wcwidth(r rune) int {
+	return DefaultCondition.Wcwidth(r)
+}
@@ -0,0 +1,131 @@
+package runewidth
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestCompatGlibc(t *testing.T) {
+	fp, err := os.Open("testdata/wcwidth-glibc.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fp.Close()
+
+	c := newCond(false)
+	c.Compat = CompatGlibc
+	lut := newCond(false)
+	lut.Compat = CompatGlibc
+	lut.CreateLUT()
+
+	var (
+		scanner = bufio.NewScanner(fp)
+		next    rune
+	)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		var first, last rune
+		var want int
+		if _, err := fmt.Sscanf(line, "%X..%X %d", &first, &last, &want); err != nil {
+			t.Fatalf("%q: %s", line, err)
+		}
+		if first != next {
+			t.Fatalf("gap before %q", line)
+		}
+		next = last + 1
+
+		wantRW := want
+		if want < 0 {
+			wantRW = 0
+		}
+		for r := first; r <= last; r++ {
+			if have := c.Wcwidth(r); have != want {
+				t.Fatalf("Wcwidth(%U) = %d, want %d", r, have, want)
+			}
+			if have := c.RuneWidth(r); have != wantRW {
+				t.Fatalf("RuneWidth(%U) = %d, want %d", r, have, wantRW)
+			}
+			if have := lut.RuneWidth(r); have != wantRW {
+				t.Fatalf("RuneWidth(%U) with LUT = %d, want %d", r, have, wantRW)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if next != 0x110000 {
+		t.Fatalf("golden data ends at %U", next)
+	}
+}
+
+func TestCompatMusl(t *testing.T) {
+	tests := []struct {
+		in   rune
+		want int
+	}{
+		{0, 0},
+		{'a', 1},
+		{0x01, -1},
+		{0x7F, -1},
+		{0x85, -1},
+		{0xAD, 1},
+		{0x0301, 0},
+		{0x200B, 0},
+		{'世', 2},
+		{0x0378, 1},
+		{0xFFFE, -1},
+		{0x1FFFF, -1},
+		{0x2A700, 2},
+		{0x3FFFD, 2},
+		{0xE0001, 0},
+		{0xE0100, 0},
+		{0xE01EF, 1},
+		{0x10FFFF, -1},
+		{0x110000, -1},
+	}
+
+	c := newCond(true)
+	c.Compat = CompatMusl
+	for _, tt := range tests {
+		if have := c.Wcwidth(tt.in); have != tt.want {
+			t.Errorf("Wcwidth(%U) = %d, want %d", tt.in, have, tt.want)
+		}
+	}
+
+	lut := newCond(false)
+	lut.Compat = CompatMusl
+	lut.CreateLUT()
+	for r := rune(0); r <= 0x10FFFF; r++ {
+		if have, want := lut.RuneWidth(r), c.RuneWidth(r); have != want {
+			t.Fatalf("%U: LUT has %d, want %d", r, have, want)
+		}
+	}
+}
+
+func TestWcwidth(t *testing.T) {
+	tests := []struct {
+		in   rune
+		want int
+	}{
+		{0, 0},
+		{'\t', -1},
+		{0x9F, -1},
+		{'a', 1},
+		{'世', 2},
+		{0x0301, 0},
+		{-1, -1},
+	}
+
+	c := newCond(false)
+	for _, tt := range tests {
+		if have := c.Wcwidth(tt.in); have != tt.want {
+			t.Errorf("Wcwidth(%U) = %d, want %d", tt.in, have, tt.want)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package runewidth
+
+import (
+	"context"
+	"unsafe"
+)
+
+// Compiled is an immutable Condition, created with Condition.Compile.
+//
+// The widths of all runes are stored in a compact lookup table, which is
+// smaller than the LUT from CreateLUT while being about as fast. A Compiled
+// is safe for concurrent use, as none of the settings can be changed.
+type Compiled struct {
+	c *Condition
+}
+
+// widthTable is a two-stage lookup table: index has the block for every 256
+// runes, and every block has the widths of 256 runes in 2 bits each. Most
+// blocks are identical, so it's much smaller than a flat LUT.
+type widthTable struct {
+	index  [0x110000 >> 8]uint16
+	blocks []byte
+}
+
+const blockSize = 256 / 4
+
+func (t *widthTable) width(r rune) int {
+	b := t.blocks[int(t.index[r>>8])*blockSize+int(r&0xff)>>2]
+	return int(b>>(uint(r&3)*2)) & 3
+}
+
+// Compile returns an immutable copy of c with all the settings and tables
+// combined in to a single lookup table.
+//
+// Changes to c after calling Compile have no effect on the Compiled.
+func (c *Condition) Compile() *Compiled {
+	cc := *c
+	cc.combinedLut = nil
+
+	lut := make([]byte, 0x110000/2)
+	_ = cc.fillLUT(context.Background(), lut)
+
+	var (
+		t    = &widthTable{blocks: make([]byte, 0, 64*blockSize)}
+		seen = make(map[string]uint16)
+	)
+	for i := range t.index {
+		var blk [blockSize]byte
+		for j := 0; j < 256; j++ {
+			r := i<<8 | j
+			w := lut[r>>1] >> (uint(r&1) * 4) & 3
+			blk[j>>2] |= w << (uint(j&3) * 2)
+		}
+		n, ok := seen[string(blk[:])]
+		if !ok {
+			n = uint16(len(t.blocks) / blockSize)
+			seen[string(blk[:])] = n
+			t.blocks = append(t.blocks, blk[:]...)
+		}
+		t.index[i] = n
+	}
+	cc.compiled = t
+	return &Compiled{c: &cc}
+}
+
+// Size returns the size of the lookup table in bytes.
+func (c *Compiled) Size() int {
+	return int(unsafe.Sizeof(c.c.compiled.index)) + len(c.c.compiled.blocks)
+}
+
+// Profile returns a snapshot of the settings.
+func (c *Compiled) Profile() Profile { return c.c.Profile() }
+
+// RuneWidth returns the number of cells in r; see Condition.RuneWidth.
+func (c *Compiled) RuneWidth(r rune) int { return c.c.RuneWidth(r) }
+
+// StringWidth returns the number of cells in s; see Condition.StringWidth.
+func (c *Compiled) StringWidth(s string) int { return c.c.StringWidth(s) }
+
+// StringWidthANSI returns the number of cells in s, ignoring ANSI escape
+// sequences; see Condition.StringWidthANSI.
+func (c *Compiled) StringWidthANSI(s string) int { return c.c.StringWidthANSI(s) }
+
+// Truncate s to at most w cells; see Condition.Truncate.
+func (c *Compiled) Truncate(s string, w int, tail string) string { return c.c.Truncate(s, w, tail) }
+
+// TruncateANSI truncates s to at most w cells, skipping ANSI escape
+// sequences; see Condition.TruncateANSI.
+func (c *Compiled) TruncateANSI(s string, w int, tail string) string {
+	return c.c.TruncateANSI(s, w, tail)
+}
+
+// Wrap s so that every line is at most w cells wide; see Condition.Wrap.
+func (c *Compiled) Wrap(s string, w int) string { return c.c.Wrap(s, w) }
+
+// WrapWith wraps s so that every line is at most w cells wide; see
+// Condition.WrapWith.
+func (c *Compiled) WrapWith(s string, w int, opts WrapOpts) string { return c.c.WrapWith(s, w, opts) }
+
+// FillLeft pads s with spaces on the left so that it's w cells wide; see
+// Condition.FillLeft.
+func (c *Compiled) FillLeft(s string, w int) string { return c.c.FillLeft(s, w) }
+
+// FillRight pads s with spaces on the right so that it's w cells wide; see
+// Condition.FillRight.
+func (c *Compiled) FillRight(s string, w int) string { return c.c.FillRight(s, w) }
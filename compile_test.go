@@ -0,0 +1,56 @@
+package runewidth
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestCompile(t *testing.T) {
+	for _, ea := range []bool{false, true} {
+		for _, cw := range []int{0, 2} {
+			t.Run(fmt.Sprintf("%t/%d", ea, cw), func(t *testing.T) {
+				c := newCond(ea)
+				c.ControlWidth = cw
+				cc := c.Compile()
+				for r := rune(-1); r <= 0x110000; r++ {
+					if have, want := cc.RuneWidth(r), c.RuneWidth(r); have != want {
+						t.Fatalf("%U: compiled has %d, want %d", r, have, want)
+					}
+				}
+				if s := cc.Size(); s > 64*1024 {
+					t.Errorf("table is %d bytes", s)
+				}
+			})
+		}
+	}
+
+	// Changing the Condition doesn't change the Compiled.
+	c := newCond(false)
+	cc := c.Compile()
+	c.EastAsianWidth = true
+	if have := cc.StringWidth("☆"); have != 1 {
+		t.Errorf("StringWidth() = %d", have)
+	}
+	if cc.Profile().EastAsianWidth {
+		t.Errorf("Profile() changed")
+	}
+}
+
+func TestCompileConcurrent(t *testing.T) {
+	cc := newCond(true).Compile()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				if w := cc.StringWidth("■㈱の世界①"); w != 12 {
+					t.Errorf("StringWidth() = %d", w)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
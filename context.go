@@ -0,0 +1,59 @@
+package runewidth
+
+import "context"
+
+type ctxKey struct{}
+
+// WithCondition returns a copy of ctx with the Condition c attached; use
+// FromContext to retrieve it.
+//
+// This allows using different conditions for different clients or requests,
+// without passing the Condition to every function.
+func WithCondition(ctx context.Context, c *Condition) context.Context {
+	return context.WithValue(ctx, ctxKey{}, c)
+}
+
+// FromContext returns the Condition attached to ctx with WithCondition, or
+// DefaultCondition if there is none.
+func FromContext(ctx context.Context) *Condition {
+	if c, ok := ctx.Value(ctxKey{}).(*Condition); ok && c != nil {
+		return c
+	}
+	return DefaultCondition
+}
+
+// RuneWidthContext returns the number of cells in r, using the Condition from
+// ctx.
+func RuneWidthContext(ctx context.Context, r rune) int {
+	return FromContext(ctx).RuneWidth(r)
+}
+
+// StringWidthContext returns the number of cells in s, using the Condition
+// from ctx.
+func StringWidthContext(ctx context.Context, s string) int {
+	return FromContext(ctx).StringWidth(s)
+}
+
+// TruncateContext truncates s to at most w cells, appending tail if s was
+// truncated, using the Condition from ctx.
+func TruncateContext(ctx context.Context, s string, w int, tail string) string {
+	return FromContext(ctx).Truncate(s, w, tail)
+}
+
+// WrapContext wraps s so that every line is at most w cells wide, using the
+// Condition from ctx.
+func WrapContext(ctx context.Context, s string, w int) string {
+	return FromContext(ctx).Wrap(s, w)
+}
+
+// FillLeftContext pads s with spaces on the left so that it's w cells wide,
+// using the Condition from ctx.
+func FillLeftContext(ctx context.Context, s string, w int) string {
+	return FromContext(ctx).FillLeft(s, w)
+}
+
+// FillRightContext pads s with spaces on the right so that it's w cells wide,
+// using the Condition from ctx.
+func FillRightContext(ctx context.Context, s string, w int) string {
+	return FromContext(ctx).FillRight(s, w)
+}
@@ -0,0 +1,40 @@
+package runewidth
+
+import (
+	"context"
+	"testing"
+)
+
+func TestContext(t *testing.T) {
+	bg := context.Background()
+	if c := FromContext(bg); c != DefaultCondition {
+		t.Errorf("FromContext(bg) = %p, want DefaultCondition", c)
+	}
+	if c := FromContext(WithCondition(bg, nil)); c != DefaultCondition {
+		t.Errorf("FromContext(nil) = %p, want DefaultCondition", c)
+	}
+
+	ctx := WithCondition(bg, newCond(true))
+	if c := FromContext(ctx); !c.EastAsianWidth {
+		t.Errorf("FromContext(ctx) doesn't have EastAsianWidth")
+	}
+
+	if have := RuneWidthContext(ctx, '★'); have != 2 {
+		t.Errorf("RuneWidthContext = %d, want 2", have)
+	}
+	if have := StringWidthContext(ctx, "★★"); have != 4 {
+		t.Errorf("StringWidthContext = %d, want 4", have)
+	}
+	if have := TruncateContext(ctx, "★★", 3, ""); have != "★" {
+		t.Errorf("TruncateContext = %q, want %q", have, "★")
+	}
+	if have := WrapContext(ctx, "★★", 2); have != "★\n★" {
+		t.Errorf("WrapContext = %q, want %q", have, "★\n★")
+	}
+	if have := FillLeftContext(ctx, "★", 3); have != " ★" {
+		t.Errorf("FillLeftContext = %q, want %q", have, " ★")
+	}
+	if have := FillRightContext(ctx, "★", 3); have != "★ " {
+		t.Errorf("FillRightContext = %q, want %q", have, "★ ")
+	}
+}
@@ -0,0 +1,145 @@
+package runewidth
+
+import (
+	"io"
+	"unicode/utf8"
+)
+
+// Counter measures the width of text that arrives in chunks, such as from an
+// io.Reader or network connection, with the same result as passing all of it
+// to StringWidth at once. Incomplete UTF-8 sequences and characters that may
+// still be extended (for example by a combining mark) are kept until the next
+// write, and are measured as they are by Width.
+//
+// The zero value is ready to use, and uses DefaultCondition.
+type Counter struct {
+	// Condition to use; DefaultCondition is used if nil.
+	Condition *Condition
+
+	pend  []byte // Input that's not yet measured.
+	width int    // Width of the input before pend.
+	max   int    // Widest part before a "\r" with NewlineCRReset.
+	line  int    // Width at the start of the current line, for tab stops.
+}
+
+func (m *Counter) cond() *Condition {
+	if m.Condition == nil {
+		return DefaultCondition
+	}
+	return m.Condition
+}
+
+// Write adds p to the text. It always returns len(p), nil.
+func (m *Counter) Write(p []byte) (int, error) {
+	m.pend = append(m.pend, p...)
+	m.measure()
+	return len(p), nil
+}
+
+// WriteString adds s to the text. It always returns len(s), nil.
+func (m *Counter) WriteString(s string) (int, error) {
+	m.pend = append(m.pend, s...)
+	m.measure()
+	return len(s), nil
+}
+
+// WriteRune adds r to the text. It always returns the length of r in bytes and
+// nil.
+func (m *Counter) WriteRune(r rune) (int, error) {
+	var b [utf8.UTFMax]byte
+	n := utf8.EncodeRune(b[:], r)
+	m.pend = append(m.pend, b[:n]...)
+	m.measure()
+	return n, nil
+}
+
+// Width returns the width of the text so far.
+func (m *Counter) Width() int {
+	t := *m
+	t.add(string(m.pend), true)
+	if t.max > t.width {
+		return t.max
+	}
+	return t.width
+}
+
+// Reset clears the text.
+func (m *Counter) Reset() {
+	m.pend = m.pend[:0]
+	m.width, m.max, m.line = 0, 0, 0
+}
+
+func (m *Counter) measure() {
+	n := m.add(bytesToString(m.pend), false)
+	m.pend = append(m.pend[:0], m.pend[n:]...)
+}
+
+// add the width of s, like StringWidth. It returns the number of bytes that
+// were measured; the characters at the end that may still be extended aren't
+// measured unless final is set.
+func (m *Counter) add(s string, final bool) int {
+	var (
+		c     = m.cond()
+		reset = c.Newlines&(NewlineCR|NewlineCRReset) == NewlineCRReset
+		i     int
+	)
+	iter := c.clusters()
+	for i < len(s) {
+		if !final && !utf8.FullRuneInString(s[i:]) {
+			break
+		}
+		switch {
+		case reset && s[i] == '\r':
+			if m.width > m.max {
+				m.max = m.width
+			}
+			m.width, m.line = 0, 0
+			i++
+			continue
+		case s[i] == '\n':
+			m.line = m.width
+		}
+		n, w := iter.at(s, i, m.width-m.line)
+		if !final && !clusterEnds(s[i+n:]) {
+			break
+		}
+		m.width += w
+		i += n
+	}
+	return i
+}
+
+// ReaderWidth returns the width of all text read from r, like StringWidth,
+// without reading all of it in memory. It returns the width so far and the
+// error if reading fails.
+func (c *Condition) ReaderWidth(r io.Reader) (int, error) {
+	m := Counter{Condition: c}
+	_, err := io.Copy(&m, r)
+	return m.Width(), err
+}
+
+// RuneReaderWidth is like ReaderWidth, but reads runes from r.
+func (c *Condition) RuneReaderWidth(r io.RuneReader) (int, error) {
+	m := Counter{Condition: c}
+	for {
+		rr, _, err := r.ReadRune()
+		if err == io.EOF {
+			return m.Width(), nil
+		}
+		if err != nil {
+			return m.Width(), err
+		}
+		m.WriteRune(rr)
+	}
+}
+
+// ReaderWidth returns the width of all text read from r, like StringWidth.
+func ReaderWidth(r io.Reader) (int, error) {
+	return DefaultCondition.ReaderWidth(r)
+}
+
+// RuneReaderWidth returns the width of all runes read from r, like
+// StringWidth.
+func RuneReaderWidth(r io.RuneReader) (int, error) {
+	return DefaultCondition.RuneReaderWidth(r)
+}
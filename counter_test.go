@@ -0,0 +1,95 @@
+package runewidth
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestCounter(t *testing.T) {
+	tests := []string{
+		"",
+		"hello",
+		"つのだ☆HIRO",
+		"a\u0301\u0302b",
+		"❤\ufe0f ❤",
+		"\U0001F1F3\U0001F1F1\U0001F1F3",
+		"\U0001F469\u200d\U0001F469\u200d\U0001F467!",
+		"a\u20dd",
+		"ab\tc\nd\te",
+		"progress 10%\rprogress 100%\rdone",
+		"\xe3\x81\xffab\xe3",
+	}
+	conds := []func(c *Condition){
+		func(c *Condition) {},
+		func(c *Condition) {
+			c.VariationSelectors, c.RegionalIndicators, c.WideEnclosing = true, true, true
+			c.ZWJ, c.Newlines = ZWJEmoji, NewlineCRReset
+		},
+		func(c *Condition) { c.EastAsianWidth, c.TabWidth = true, 4 },
+	}
+	for i, set := range conds {
+		c := newCond(false)
+		set(c)
+		for _, s := range tests {
+			want := c.StringWidth(s)
+			for _, n := range []int{1, 2, 3, 1000} {
+				t.Run(fmt.Sprintf("%d/%q/%d", i, s, n), func(t *testing.T) {
+					m := Counter{Condition: c}
+					for j := 0; j < len(s); j += n {
+						end := j + n
+						if end > len(s) {
+							end = len(s)
+						}
+						m.Write([]byte(s[j:end]))
+					}
+					if have := m.Width(); have != want {
+						t.Errorf("Width = %d, want %d", have, want)
+					}
+					if have := m.Width(); have != want {
+						t.Errorf("second Width = %d, want %d", have, want)
+					}
+				})
+			}
+
+			m := Counter{Condition: c}
+			for _, r := range s {
+				m.WriteRune(r)
+			}
+			if have := m.Width(); have != want {
+				t.Errorf("%d/%q: WriteRune: Width = %d, want %d", i, s, have, want)
+			}
+		}
+	}
+
+	var m Counter
+	m.WriteString("abc")
+	m.Reset()
+	m.WriteString("x")
+	if have := m.Width(); have != 1 {
+		t.Errorf("after Reset: %d", have)
+	}
+}
+
+func TestReaderWidth(t *testing.T) {
+	c := newCond(false)
+	c.VariationSelectors = true
+	s := strings.Repeat("つのだ☆HIRO ❤\ufe0f a\u0301\n", 5000)
+	want := c.StringWidth(s)
+
+	if have, err := c.ReaderWidth(iotest.OneByteReader(strings.NewReader(s))); err != nil || have != want {
+		t.Errorf("ReaderWidth = %d, %v; want %d", have, err, want)
+	}
+	if have, err := c.RuneReaderWidth(bufio.NewReader(strings.NewReader(s))); err != nil || have != want {
+		t.Errorf("RuneReaderWidth = %d, %v; want %d", have, err, want)
+	}
+
+	if have, err := c.ReaderWidth(iotest.TimeoutReader(strings.NewReader("abc"))); err != iotest.ErrTimeout || have != 3 {
+		t.Errorf("ReaderWidth = %d, %v; want 3, %v", have, err, iotest.ErrTimeout)
+	}
+	if have, err := c.RuneReaderWidth(bufio.NewReader(iotest.TimeoutReader(strings.NewReader("abc")))); err != iotest.ErrTimeout || have != 3 {
+		t.Errorf("RuneReaderWidth = %d, %v; want 3, %v", have, err, iotest.ErrTimeout)
+	}
+}
@@ -0,0 +1,82 @@
+package runewidth
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// Ruler writes s to w with a column ruler below it, for debugging width
+// problems.
+//
+// The first line below s marks every cell: "^" is the first cell of a
+// character, "~" a continuation cell of a wide character. Below that is a
+// ruler with the column numbers (0-based), and finally a list of all
+// zero-width characters and the column they're attached to. For example:
+//
+//	a世b́
+//	^^~^
+//	0123
+//	col 3: U+0301 zero width
+func (c *Condition) Ruler(w io.Writer, s string) error {
+	var (
+		bw     = bufio.NewWriter(w)
+		marks  = make([]byte, 0, len(s))
+		zero   []string
+		width  int
+		tens   bool
+		digits = "0123456789"
+	)
+	iter := c.clusters()
+	for i := 0; i < len(s); {
+		n, cw := iter.at(s, i, width)
+		if cw > 0 {
+			marks = append(marks, '^')
+			for j := 1; j < cw; j++ {
+				marks = append(marks, '~')
+			}
+			width += cw
+		}
+		for j, r := range s[i : i+n] {
+			if j == 0 && cw > 0 {
+				continue
+			}
+			col := width - 1
+			if col < 0 {
+				col = 0
+			}
+			zero = append(zero, fmt.Sprintf("col %d: %U zero width", col, r))
+		}
+		i += n
+	}
+
+	fmt.Fprintln(bw, s)
+	fmt.Fprintf(bw, "%s\n", marks)
+	for i := 0; i < width; i++ {
+		bw.WriteByte(digits[i%10])
+		if i >= 10 {
+			tens = true
+		}
+	}
+	bw.WriteByte('\n')
+	if tens {
+		for i := 0; i < width; i++ {
+			if i < 10 {
+				bw.WriteByte(' ')
+			} else {
+				bw.WriteByte(digits[(i/10)%10])
+			}
+		}
+		bw.WriteByte('\n')
+	}
+	for _, z := range zero {
+		fmt.Fprintln(bw, z)
+	}
+	return bw.Flush()
+}
+
+// Ruler writes s to w with a column ruler below it, for debugging width
+// problems.
+func Ruler(w io.Writer, s string) error {
+	return DefaultCondition.Ruler(w, s)
+}
@@ -0,0 +1,28 @@
+package runewidth
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRuler(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"", "\n\n\n"},
+		{"a世b́", "a世b́\n^^~^\n0123\ncol 3: U+0301 zero width\n"},
+		{"́a", "́a\n^\n0\ncol 0: U+0301 zero width\n"},
+		{"abcdefghij世", "abcdefghij世\n^^^^^^^^^^^~\n012345678901\n          11\n"},
+	}
+
+	c := newCond(false)
+	for _, tt := range tests {
+		b := new(strings.Builder)
+		if err := c.Ruler(b, tt.in); err != nil {
+			t.Fatal(err)
+		}
+		if b.String() != tt.want {
+			t.Errorf("Ruler(%q)\nhave:\n%s\nwant:\n%s", tt.in, b, tt.want)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package runewidth
+
+import "strings"
+
+// AlignPair prepares a pair of old and new lines for a side-by-side diff.
+//
+// Tabs are expanded to spaces up to the next tab stop (see TabWidth), and both
+// lines are truncated to w cells (appending tail) and padded with spaces so
+// that they're exactly w cells wide. If w <= 0 then the lines are never truncated, and the
+// narrower line is padded to the width of the wider line.
+//
+// Any line break at the end of the lines is removed.
+func (c *Condition) AlignPair(old, new string, w int, tail string) (string, string) {
+	old, new = c.expandTabs(c.trimBreak(old)), c.expandTabs(c.trimBreak(new))
+	if w <= 0 {
+		w = c.StringWidth(old)
+		if nw := c.StringWidth(new); nw > w {
+			w = nw
+		}
+	}
+	return c.FillRight(c.Truncate(old, w, tail), w), c.FillRight(c.Truncate(new, w, tail), w)
+}
+
+// expandTabs replaces tabs in s with spaces up to the next tab stop.
+func (c *Condition) expandTabs(s string) string {
+	if strings.IndexByte(s, '\t') == -1 {
+		return s
+	}
+	var (
+		b   strings.Builder
+		col int
+	)
+	b.Grow(len(s) + 8)
+	iter := c.clusters()
+	for i := 0; i < len(s); {
+		if s[i] == '\n' {
+			col = 0
+		}
+		n, cw := iter.at(s, i, col)
+		if s[i] == '\t' {
+			b.WriteString(strings.Repeat(" ", cw))
+			col += cw
+			i++
+			continue
+		}
+		b.WriteString(s[i : i+n])
+		col += cw
+		i += n
+	}
+	return b.String()
+}
+
+// AlignPair prepares a pair of old and new lines for a side-by-side diff.
+func AlignPair(old, new string, w int, tail string) (string, string) {
+	return DefaultCondition.AlignPair(old, new, w, tail)
+}
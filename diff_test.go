@@ -0,0 +1,28 @@
+package runewidth
+
+import "testing"
+
+func TestAlignPair(t *testing.T) {
+	tests := []struct {
+		old, new   string
+		w          int
+		wOld, wNew string
+	}{
+		{"", "", 0, "", ""},
+		{"abc", "a", 0, "abc", "a  "},
+		{"abc\n", "東京\n", 0, "abc ", "東京"},
+		{"\tx", "  x", 0, "        x", "  x      "},
+		{"a\tx", "東\tx", 0, "a       x", "東      x"},
+		{"abcdef", "東京大阪", 5, "abcd…", "東京…"},
+		{"  ab", "", 5, "  ab ", "     "},
+	}
+
+	c := newCond(false)
+	for _, tt := range tests {
+		o, n := c.AlignPair(tt.old, tt.new, tt.w, "…")
+		if o != tt.wOld || n != tt.wNew {
+			t.Errorf("AlignPair(%q, %q, %d) = (%q, %q), want (%q, %q)",
+				tt.old, tt.new, tt.w, o, n, tt.wOld, tt.wNew)
+		}
+	}
+}
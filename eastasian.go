@@ -0,0 +1,59 @@
+package runewidth
+
+// EastAsianWidthValue is a value of the East Asian Width property from
+// Unicode Standard Annex #11.
+type EastAsianWidthValue uint8
+
+// Values of the East Asian Width property.
+const (
+	EANeutral   EastAsianWidthValue = iota // N
+	EAAmbiguous                            // A
+	EAHalfwidth                            // H
+	EAWide                                 // W
+	EAFullwidth                            // F
+	EANarrow                               // Na
+)
+
+// String returns the abbreviation used in the Unicode Character Database,
+// such as "Na".
+func (v EastAsianWidthValue) String() string {
+	switch v {
+	case EAAmbiguous:
+		return "A"
+	case EAHalfwidth:
+		return "H"
+	case EAWide:
+		return "W"
+	case EAFullwidth:
+		return "F"
+	case EANarrow:
+		return "Na"
+	}
+	return "N"
+}
+
+// EastAsianWidthProperty returns the value of the East Asian Width property of
+// r, as listed in EastAsianWidth.txt from the version of the Unicode Character
+// Database returned by UnicodeVersion(). Code points that aren't listed,
+// including invalid ones, are EANeutral.
+//
+// This is the property as-is, regardless of any settings; use RuneWidth for
+// the width and WidthClass for a classification that includes combining
+// marks and emoji.
+func EastAsianWidthProperty(r rune) EastAsianWidthValue {
+	switch {
+	case r < 0 || r > 0x10FFFF:
+		return EANeutral
+	case inTable(r, fullwidth):
+		return EAFullwidth
+	case inTable(r, halfwidth):
+		return EAHalfwidth
+	case inTable(r, doublewidth):
+		return EAWide
+	case inTable(r, ambiguous):
+		return EAAmbiguous
+	case inTable(r, narrow):
+		return EANarrow
+	}
+	return EANeutral
+}
@@ -0,0 +1,36 @@
+package runewidth
+
+import "testing"
+
+func TestEastAsianWidthProperty(t *testing.T) {
+	tests := []struct {
+		in   rune
+		want string
+	}{
+		{'A', "Na"},
+		{'¡', "A"},
+		{'\u00a0', "N"},
+		{'₩', "H"},
+		{'\u3000', "F"},
+		{'Ａ', "F"},
+		{'ｱ', "H"},
+		{'世', "W"},
+		{'😀', "W"},
+		{0xE000, "A"},
+		{'☆', "A"},
+		{'\u0300', "A"},
+		{0, "N"},
+		{'⟦', "Na"},
+		{'가', "W"},
+		{0x1100, "W"},
+		{0x20000, "W"},
+		{-1, "N"},
+		{0x110000, "N"},
+	}
+
+	for _, tt := range tests {
+		if have := EastAsianWidthProperty(tt.in).String(); have != tt.want {
+			t.Errorf("EastAsianWidthProperty(%U) = %s, want %s", tt.in, have, tt.want)
+		}
+	}
+}
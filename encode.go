@@ -0,0 +1,145 @@
+package runewidth
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Range is an inclusive range of code points.
+type Range struct {
+	First, Last rune
+}
+
+// tableVersion is the version of the binary format written by WriteTables.
+const tableVersion = 1
+
+var tableMagic = [4]byte{'R', 'W', 'T', 'B'}
+
+// allTables are all the tables that WriteTables writes.
+var allTables = map[string]table{
+	"ambiguous":   ambiguous,
+	"combining":   combining,
+	"contested":   contested,
+	"doublewidth": doublewidth,
+	"emoji":       emoji,
+	"narrow":      narrow,
+	"neutral":     neutral,
+	"nonprint":    nonprint,
+}
+
+// WriteTables writes all the range tables used by this package to w, in a
+// compact binary format that can be read by ReadTables or tools in other
+// languages.
+//
+// The format is:
+//
+//	"RWTB"                   magic
+//	version                  1 byte; currently 1
+//	ntables                  uvarint
+//	for every table, sorted by name:
+//	  len(name), name        uvarint, bytes; at most 255 bytes
+//	  nranges                uvarint
+//	  for every range:
+//	    first - prev.last    uvarint; prev.last is 0 for the first range
+//	    last - first         uvarint
+//
+// The uvarints are encoded like encoding/binary (LEB128).
+func WriteTables(w io.Writer) error {
+	names := make([]string, 0, len(allTables))
+	for n := range allTables {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	var (
+		bw  = bufio.NewWriter(w)
+		buf [binary.MaxVarintLen64]byte
+	)
+	uvarint := func(v uint64) {
+		bw.Write(buf[:binary.PutUvarint(buf[:], v)])
+	}
+	bw.Write(tableMagic[:])
+	bw.WriteByte(tableVersion)
+	uvarint(uint64(len(names)))
+	for _, n := range names {
+		t := allTables[n]
+		uvarint(uint64(len(n)))
+		bw.WriteString(n)
+		uvarint(uint64(len(t)))
+		var prev rune
+		for _, iv := range t {
+			uvarint(uint64(iv.first - prev))
+			uvarint(uint64(iv.last - iv.first))
+			prev = iv.last
+		}
+	}
+	return bw.Flush()
+}
+
+// ReadTables reads tables written by WriteTables.
+func ReadTables(r io.Reader) (map[string][]Range, error) {
+	br := bufio.NewReader(r)
+
+	var magic [4]byte
+	if _, err := io.ReadFull(br, magic[:]); err != nil || magic != tableMagic {
+		return nil, errors.New("runewidth.ReadTables: not a table file")
+	}
+	v, err := br.ReadByte()
+	if err != nil {
+		return nil, fmt.Errorf("runewidth.ReadTables: %w", err)
+	}
+	if v != tableVersion {
+		return nil, fmt.Errorf("runewidth.ReadTables: unsupported version %d", v)
+	}
+
+	uvarint := func() uint64 {
+		if err != nil {
+			return 0
+		}
+		var n uint64
+		n, err = binary.ReadUvarint(br)
+		return n
+	}
+	ntables := uvarint()
+	tables := make(map[string][]Range)
+	for i := uint64(0); i < ntables && err == nil; i++ {
+		// The lengths come from the input, so don't allocate more than is
+		// actually read.
+		var name strings.Builder
+		n := uvarint()
+		if err == nil && n > 255 {
+			err = errors.New("name too long")
+		}
+		if err == nil {
+			_, err = io.CopyN(&name, br, int64(n))
+		}
+		nranges := uvarint()
+		if err == nil && nranges > 0x110000 {
+			err = errors.New("too many ranges")
+		}
+		var t []Range
+		var prev rune
+		for j := uint64(0); j < nranges && err == nil; j++ {
+			first := prev + rune(uvarint())
+			last := first + rune(uvarint())
+			if first < prev || last < first || last > 0x10FFFF {
+				err = errors.New("invalid range")
+			}
+			t = append(t, Range{first, last})
+			prev = last
+		}
+		tables[name.String()] = t
+	}
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, fmt.Errorf("runewidth.ReadTables: %w", err)
+	}
+	return tables, nil
+}
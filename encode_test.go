@@ -0,0 +1,56 @@
+package runewidth
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestReadWriteTables(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteTables(&buf); err != nil {
+		t.Fatal(err)
+	}
+	b := buf.Bytes()
+
+	tables, err := ReadTables(bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tables) != len(allTables) {
+		t.Fatalf("read %d tables, want %d", len(tables), len(allTables))
+	}
+	for name, want := range allTables {
+		have := tables[name]
+		if len(have) != len(want) {
+			t.Errorf("%s: %d ranges, want %d", name, len(have), len(want))
+			continue
+		}
+		for i := range want {
+			if have[i].First != want[i].first || have[i].Last != want[i].last {
+				t.Errorf("%s[%d] = %v, want %v", name, i, have[i], want[i])
+			}
+		}
+	}
+
+	tests := []struct {
+		in      []byte
+		wantErr string
+	}{
+		{nil, "not a table file"},
+		{[]byte("RWTB"), "EOF"},
+		{[]byte("RWTB\x02"), "unsupported version 2"},
+		{b[:len(b)/2], "unexpected EOF"},
+		{[]byte("RWTB\x01\x01\x01x\x01\x00\xff\xff\xff\xff\x0f"), "invalid range"},
+		{[]byte("RWTB\x01\x01\xff\xff\xff\xff\xff\xff\xff\xff\x7f"), "name too long"},
+		{[]byte("RWTB\x01\x01\x80\x01abc"), "unexpected EOF"},
+		{[]byte("RWTB\x01\xff\xff\xff\xff\x0f\x01x\xff\xff\xff\xff\x0f"), "too many ranges"},
+		{[]byte("RWTB\x01\x01\x01x\xff\xff\x43"), "unexpected EOF"},
+	}
+	for _, tt := range tests {
+		_, err := ReadTables(bytes.NewReader(tt.in))
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("ReadTables(%q): wrong error: %v", tt.in, err)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package runewidth
+
+import (
+	"os"
+	"strings"
+)
+
+// ConditionFromEnv returns a new Condition for the terminal and locale in the
+// environment.
+//
+// The terminal emulator is detected from the TERM_PROGRAM, WT_SESSION, and
+// TERM environment variables, and if it's known the settings from
+// ConditionForTerminal are used. EastAsianWidth is set from the locale, and
+// AmbiguousLocale is set to the language from LC_ALL, LC_CTYPE, or LANG if
+// it's one of AmbiguousLocales(). RUNEWIDTH_EASTASIAN overrides
+// EastAsianWidth, as with DetectProfile.
+func ConditionFromEnv() *Condition {
+	p, _ := DetectProfile(probeEnv)
+	return p.Condition()
+}
+
+// probeEnv sets the terminal and ambiguous locale settings from the
+// environment.
+func probeEnv(p *Profile) error {
+	if t, ok := terminals[envTerminal()]; ok {
+		t.EastAsianWidth = p.EastAsianWidth
+		*p = t
+	}
+	p.AmbiguousLocale = envAmbiguousLocale()
+	return nil
+}
+
+// envTerminal returns the name of the terminal emulator from the environment,
+// or "" if it's not known.
+//
+// tmux is checked first as it measures characters itself regardless of the
+// outer terminal, which may still set TERM_PROGRAM or WT_SESSION.
+func envTerminal() string {
+	term := os.Getenv("TERM")
+	switch {
+	case os.Getenv("TMUX") != "", strings.HasPrefix(term, "tmux"):
+		return "tmux"
+	case os.Getenv("TERM_PROGRAM") == "WezTerm":
+		return "wezterm"
+	case os.Getenv("WT_SESSION") != "":
+		return "windows-terminal"
+	case os.Getenv("XTERM_VERSION") != "":
+		// Many terminals set TERM=xterm-256color; only xterm sets this.
+		return "xterm"
+	}
+	switch term {
+	case "xterm-kitty":
+		return "kitty"
+	case "alacritty", "wezterm":
+		return term
+	}
+	return ""
+}
+
+// envAmbiguousLocale returns the AmbiguousLocale for the locale in LC_ALL,
+// LC_CTYPE, or LANG, or "" if there isn't one.
+func envAmbiguousLocale() string {
+	locale := os.Getenv("LC_ALL")
+	if locale == "" {
+		locale = os.Getenv("LC_CTYPE")
+	}
+	if locale == "" {
+		locale = os.Getenv("LANG")
+	}
+	if i := strings.IndexAny(locale, ".@"); i > -1 {
+		locale = locale[:i]
+	}
+
+	lang, region := locale, ""
+	if i := strings.IndexAny(locale, "_-"); i > -1 {
+		lang, region = locale[:i], locale[i+1:]
+	}
+	switch lang {
+	case "ja", "ko":
+		return lang
+	case "zh":
+		switch region {
+		case "TW", "HK", "MO", "Hant":
+			return "zh-Hant"
+		}
+		return "zh-Hans"
+	}
+	return ""
+}
@@ -0,0 +1,98 @@
+package runewidth
+
+import (
+	"os"
+	"testing"
+)
+
+// clearEnv clears all environment variables that ConditionFromEnv uses, and
+// restores them after the test.
+func clearEnv(t *testing.T) {
+	for _, k := range []string{"TERM", "TERM_PROGRAM", "TMUX", "WT_SESSION",
+		"XTERM_VERSION", "LC_ALL", "LC_CTYPE", "LANG", "RUNEWIDTH_EASTASIAN",
+		"RUNEWIDTH_AMBIGUOUS"} {
+		k, v := k, os.Getenv(k)
+		os.Unsetenv(k)
+		t.Cleanup(func() { os.Setenv(k, v) })
+	}
+}
+
+func TestEnvTerminal(t *testing.T) {
+	tests := []struct {
+		env  map[string]string
+		want string
+	}{
+		{nil, ""},
+		{map[string]string{"TERM": "xterm-256color"}, ""},
+		{map[string]string{"TERM": "xterm-kitty"}, "kitty"},
+		{map[string]string{"TERM": "alacritty"}, "alacritty"},
+		{map[string]string{"TERM": "xterm-256color", "TERM_PROGRAM": "WezTerm"}, "wezterm"},
+		{map[string]string{"TERM": "xterm-256color", "XTERM_VERSION": "XTerm(388)"}, "xterm"},
+		{map[string]string{"WT_SESSION": "c2b4b7b1"}, "windows-terminal"},
+		{map[string]string{"WT_SESSION": "c2b4b7b1", "TERM": "tmux-256color"}, "tmux"},
+		{map[string]string{"TERM_PROGRAM": "WezTerm", "TMUX": "/tmp/tmux-1000/default,1,0"}, "tmux"},
+	}
+
+	for _, tt := range tests {
+		t.Run("", func(t *testing.T) {
+			clearEnv(t)
+			for k, v := range tt.env {
+				os.Setenv(k, v)
+			}
+			if have := envTerminal(); have != tt.want {
+				t.Errorf("%v: have %q, want %q", tt.env, have, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnvAmbiguousLocale(t *testing.T) {
+	tests := []struct {
+		lcall, lang, want string
+	}{
+		{"", "", ""},
+		{"", "C", ""},
+		{"", "en_US.UTF-8", ""},
+		{"", "ja_JP.UTF-8", "ja"},
+		{"", "ko_KR.EUC-KR", "ko"},
+		{"", "zh_CN.GB2312", "zh-Hans"},
+		{"", "zh_TW.Big5", "zh-Hant"},
+		{"", "zh_HK.UTF-8", "zh-Hant"},
+		{"", "zh", "zh-Hans"},
+		{"ko_KR.UTF-8", "ja_JP.UTF-8", "ko"},
+		{"ja_JP.UTF-8@cjk_narrow", "", "ja"},
+	}
+
+	for _, tt := range tests {
+		t.Run("", func(t *testing.T) {
+			clearEnv(t)
+			os.Setenv("LC_ALL", tt.lcall)
+			os.Setenv("LANG", tt.lang)
+			if have := envAmbiguousLocale(); have != tt.want {
+				t.Errorf("LC_ALL=%q LANG=%q: have %q, want %q", tt.lcall, tt.lang, have, tt.want)
+			}
+		})
+	}
+}
+
+func TestConditionFromEnv(t *testing.T) {
+	clearEnv(t)
+	os.Setenv("TERM", "xterm-kitty")
+	os.Setenv("LANG", "ko_KR.UTF-8")
+	os.Setenv("RUNEWIDTH_EASTASIAN", "1")
+
+	have := ConditionFromEnv().Profile()
+	want := terminals["kitty"]
+	want.EastAsianWidth, want.AmbiguousLocale = true, "ko"
+	if have != want {
+		t.Errorf("\nhave: %s\nwant: %s", have, want)
+	}
+
+	clearEnv(t)
+	os.Setenv("RUNEWIDTH_EASTASIAN", "0")
+	have = ConditionFromEnv().Profile()
+	want = Profile{StrictEmojiNeutral: StrictEmojiNeutral}
+	if have != want {
+		t.Errorf("\nhave: %s\nwant: %s", have, want)
+	}
+}
@@ -0,0 +1,208 @@
+package runewidth
+
+import "fmt"
+
+// Layer is a step in determining the width of a rune; see Explain.
+type Layer uint8
+
+// Layers, in order of precedence: the first layer that applies to a rune
+// determines its width.
+const (
+	LayerInvalid    Layer = iota // Not a valid code point: 0 cells.
+	LayerOverride                // Set with SetWidth or SetRangeWidth.
+	LayerControl                 // C0 and C1 controls with ControlWidth.
+	LayerNerdFonts               // Nerd Fonts icons with NerdFonts.
+	LayerPrivateUse              // Private Use Area with PrivateUseWidth.
+	LayerSymbols                 // Musical and mathematical symbols with WideSymbols.
+	LayerCompat                  // The C library's wcwidth() with Compat.
+	LayerZeroWidth               // Non-printable characters and combining marks: 0 cells.
+	LayerNarrow                  // Narrow and halfwidth characters: 1 cell.
+	LayerWide                    // Wide and fullwidth characters: 2 cells.
+	LayerAmbiguous               // Ambiguous characters, depending on EastAsianWidth and AmbiguousLocale.
+	LayerEmoji                   // Emoji, which are wide if StrictEmojiNeutral is false.
+	LayerDefault                 // Everything else: 1 cell.
+)
+
+func (l Layer) String() string {
+	switch l {
+	case LayerInvalid:
+		return "invalid"
+	case LayerOverride:
+		return "override"
+	case LayerControl:
+		return "control"
+	case LayerNerdFonts:
+		return "nerd fonts"
+	case LayerPrivateUse:
+		return "private use"
+	case LayerSymbols:
+		return "symbols"
+	case LayerCompat:
+		return "compat"
+	case LayerZeroWidth:
+		return "zero width"
+	case LayerNarrow:
+		return "narrow"
+	case LayerWide:
+		return "wide"
+	case LayerAmbiguous:
+		return "ambiguous"
+	case LayerEmoji:
+		return "emoji"
+	case LayerDefault:
+		return "default"
+	}
+	return fmt.Sprintf("Layer(%d)", uint8(l))
+}
+
+// Explanation describes how the width of a rune was determined.
+type Explanation struct {
+	Rune  rune
+	Width int
+	Layer Layer  // Layer that determined the width.
+	Range Range  // Range of runes in the Unicode table for the layer.
+	Field string // Condition field that affected the width, if any.
+}
+
+func (e Explanation) String() string {
+	s := fmt.Sprintf("%U %q has width %d: %s", e.Rune, e.Rune, e.Width, e.Layer)
+	if e.Range.First != e.Range.Last {
+		s += fmt.Sprintf(" (%U..%U)", e.Range.First, e.Range.Last)
+	}
+	if e.Field != "" {
+		s += "; set by Condition." + e.Field
+	}
+	return s
+}
+
+// Explain returns how the width of r is determined, for debugging why a rune
+// has the width it has. The width is always identical to RuneWidth().
+//
+// The settings in a Condition are usually determined in this order, where
+// later steps override earlier ones:
+//
+//  1. The defaults from NewCondition or DetectProfile.
+//  2. The locale, for EastAsianWidth.
+//  3. Probes passed to DetectProfile, such as querying the terminal.
+//  4. The RUNEWIDTH_EASTASIAN and RUNEWIDTH_AMBIGUOUS environment variables.
+//  5. Fields set on the Condition by the application, and SetWidth.
+//
+// The width of a rune is then determined by the first Layer that applies.
+func (c *Condition) Explain(r rune) Explanation {
+	e := Explanation{Rune: r, Range: Range{r, r}}
+	set := func(l Layer, w int, t table, field string) Explanation {
+		e.Layer, e.Width, e.Field = l, w, field
+		if t != nil {
+			e.Range = findRange(r, t)
+		}
+		return e
+	}
+
+	version := ""
+	if c.UnicodeVersion != "" {
+		version = "UnicodeVersion"
+	}
+	ambiguousField := "EastAsianWidth"
+	if c.AmbiguousWidth == 1 || c.AmbiguousWidth == 2 {
+		ambiguousField = "AmbiguousWidth"
+	}
+	if o := c.overrideAt(r); o != nil {
+		e.Range = Range{o.first, o.last}
+		return set(LayerOverride, int(o.width), nil, "")
+	}
+	switch {
+	case r < 0 || r > 0x10FFFF:
+		return set(LayerInvalid, 0, nil, "")
+	case r <= 0x9F && c.ControlWidth != 0 && isControl(r) && r != '\t' && r != '\n':
+		return set(LayerControl, c.controlWidth(), nil, "ControlWidth")
+	case c.NerdFonts && inTable(r, nerdFonts):
+		return set(LayerNerdFonts, 1, nerdFonts, "NerdFonts")
+	case c.NerdFonts && inTable(r, nerdFontsWide):
+		return set(LayerNerdFonts, 2, nerdFontsWide, "NerdFonts")
+	case c.privateUseWidth() > 0 && isPrivateUse(r):
+		return set(LayerPrivateUse, c.privateUseWidth(), private, "PrivateUseWidth")
+	case c.WideSymbols && inTable(r, symbols):
+		return set(LayerSymbols, 2, symbols, "WideSymbols")
+	case c.Compat != CompatNone:
+		zero, wide, np, _ := c.compatTables()
+		for _, t := range []table{zero, wide, np} {
+			if inTable(r, t) {
+				return set(LayerCompat, c.compatWidth(r), t, "Compat")
+			}
+		}
+		return set(LayerCompat, 1, nil, "Compat")
+	}
+
+	if !c.EastAsianWidth {
+		switch {
+		case r < 0x20 || (r >= 0x7F && r <= 0x9F) || r == 0xAD:
+			return set(LayerZeroWidth, 0, nonprint, "")
+		case c.AmbiguousWidth == 2 && inTable(r, ambiguous) && !inTables(r, nonprint, combining):
+			if _, ok := ambiguousLocales[c.AmbiguousLocale]; ok {
+				ambiguousField = "AmbiguousLocale"
+			}
+			if c.ambiguousWide(r) {
+				return set(LayerAmbiguous, 2, ambiguous, ambiguousField)
+			}
+			return set(LayerAmbiguous, 1, ambiguous, ambiguousField)
+		case r < 0x300 && inTable(r, ambiguous):
+			return set(LayerAmbiguous, 1, ambiguous, ambiguousField)
+		case r < 0x300:
+			return set(LayerNarrow, 1, nil, "")
+		case inTable(r, narrow):
+			return set(LayerNarrow, 1, narrow, "")
+		case inTable(r, nonprint):
+			return set(LayerZeroWidth, 0, nonprint, "")
+		case inTable(r, combining):
+			return set(LayerZeroWidth, 0, combining, "")
+		case inTable(r, c.doublewidth()):
+			return set(LayerWide, 2, c.doublewidth(), version)
+		case inTable(r, ambiguous):
+			return set(LayerAmbiguous, 1, ambiguous, ambiguousField)
+		}
+		return set(LayerDefault, 1, nil, "")
+	}
+
+	switch {
+	case inTable(r, nonprint):
+		return set(LayerZeroWidth, 0, nonprint, "")
+	case inTable(r, combining):
+		return set(LayerZeroWidth, 0, combining, "")
+	case inTable(r, narrow):
+		return set(LayerNarrow, 1, narrow, "")
+	case inTable(r, c.doublewidth()):
+		return set(LayerWide, 2, c.doublewidth(), version)
+	case inTable(r, ambiguous):
+		if c.AmbiguousWidth == 1 {
+			return set(LayerAmbiguous, 1, ambiguous, ambiguousField)
+		}
+		if _, ok := ambiguousLocales[c.AmbiguousLocale]; ok {
+			ambiguousField = "AmbiguousLocale"
+		}
+		if c.ambiguousWide(r) {
+			return set(LayerAmbiguous, 2, ambiguous, ambiguousField)
+		}
+		return set(LayerAmbiguous, 1, ambiguous, ambiguousField)
+	case inTable(r, emoji):
+		if !c.StrictEmojiNeutral {
+			return set(LayerEmoji, 2, emoji, "StrictEmojiNeutral")
+		}
+		return set(LayerEmoji, 1, emoji, "StrictEmojiNeutral")
+	}
+	return set(LayerDefault, 1, nil, "")
+}
+
+// findRange returns the range in t that contains r.
+func findRange(r rune, t table) Range {
+	for _, iv := range t {
+		if r >= iv.first && r <= iv.last {
+			return Range{iv.first, iv.last}
+		}
+	}
+	return Range{r, r}
+}
+
+// Explain returns how the width of r is determined; see Condition.Explain.
+func Explain(r rune) Explanation {
+	return DefaultCondition.Explain(r)
+}
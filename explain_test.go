@@ -0,0 +1,64 @@
+package runewidth
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestExplain(t *testing.T) {
+	tests := []struct {
+		in        rune
+		eastAsian bool
+		want      string
+	}{
+		{'a', false, `U+0061 'a' has width 1: narrow`},
+		{'\x01', false, `U+0001 '\x01' has width 0: zero width (U+0000..U+001F)`},
+		{'é', false, `U+00E9 'é' has width 1: ambiguous (U+00E8..U+00EA); set by Condition.EastAsianWidth`},
+		{'é', true, `U+00E9 'é' has width 2: ambiguous (U+00E8..U+00EA); set by Condition.EastAsianWidth`},
+		{'世', false, `U+4E16 '世' has width 2: wide (U+4E00..U+A48C)`},
+		{'́', true, `U+0301 '́' has width 0: zero width (U+0300..U+036F)`},
+		{'☺', false, `U+263A '☺' has width 1: default`},
+		{'☺', true, `U+263A '☺' has width 1: emoji (U+2614..U+2685); set by Condition.StrictEmojiNeutral`},
+	}
+
+	for _, tt := range tests {
+		c := newCond(tt.eastAsian)
+		if have := c.Explain(tt.in).String(); have != tt.want {
+			t.Errorf("\nhave: %s\nwant: %s", have, tt.want)
+		}
+	}
+}
+
+// Explain must always return the same width as RuneWidth.
+func TestExplainWidth(t *testing.T) {
+	for _, compat := range []Compat{CompatGlibc, CompatMusl} {
+		c := newCond(false)
+		c.Compat, c.PrivateUseWidth = compat, 2
+		for r := rune(-1); r < 0x40000; r++ {
+			if have, want := c.Explain(r).Width, c.RuneWidth(r); have != want {
+				t.Fatalf("%U: Explain has %d, RuneWidth has %d (%s)", r, have, want, c.Explain(r))
+			}
+		}
+	}
+
+	for _, ea := range []bool{false, true} {
+		for _, strict := range []bool{false, true} {
+			for _, cw := range []int{0, 2} {
+				for _, loc := range []string{"", "ko"} {
+					for _, aw := range []int{0, 1, 2} {
+						t.Run(fmt.Sprintf("%t/%t/%d/%s/%d", ea, strict, cw, loc, aw), func(t *testing.T) {
+							c := newCond(ea)
+							c.StrictEmojiNeutral, c.ControlWidth, c.AmbiguousLocale = strict, cw, loc
+							c.AmbiguousWidth = aw
+							for r := rune(-1); r < 0x40000; r++ {
+								if have, want := c.Explain(r).Width, c.RuneWidth(r); have != want {
+									t.Fatalf("%U: Explain has %d, RuneWidth has %d (%s)", r, have, want, c.Explain(r))
+								}
+							}
+						})
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package runewidth
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// fallbacks are ASCII replacements for characters that don't display well on
+// all terminals. The first entry with the same width as the character is
+// used, or the first entry if none have the same width.
+var fallbacks = map[rune][]string{
+	'–':      {"-", "--"},  // En dash
+	'—':      {"-", "--"},  // Em dash
+	'⸺':      {"-", "--"},  // Two-em dash
+	'⸻':      {"-", "---"}, // Three-em dash
+	'‘':      {"'"},
+	'’':      {"'"},
+	'“':      {`"`},
+	'”':      {`"`},
+	'•':      {"*", "* "},
+	'·':      {".", ". "},
+	'←':      {"<", "<-"},
+	'→':      {">", "->"},
+	'↑':      {"^", "^ "},
+	'↓':      {"v", "v "},
+	'×':      {"x", "x "},
+	'\u00ad': {""}, // Soft hyphen
+	'☀':      {":sunny:"},
+	'☎':      {":phone:"},
+	'☺':      {":relaxed:"},
+	'⚠':      {":warning:"},
+	'✂':      {":scissors:"},
+	'✈':      {":airplane:"},
+	'✔':      {":heavy_check_mark:"},
+	'❤':      {":heart:"},
+}
+
+// Fallback returns a replacement for r that displays the same on all
+// terminals, preferring a replacement with the same width as r has in this
+// Condition.
+//
+// For example "—" is replaced with "-" or "--" depending on EastAsianWidth,
+// regional indicators are replaced with the corresponding ASCII letter, and
+// some emoji are replaced with a shortcode such as ":heart:". The second
+// return value is false if there is no known replacement.
+func (c *Condition) Fallback(r rune) (string, bool) {
+	if r >= 0x1F1E6 && r <= 0x1F1FF {
+		return string('A' + r - 0x1F1E6), true
+	}
+	f, ok := fallbacks[r]
+	if !ok {
+		return "", false
+	}
+	w := c.RuneWidth(r)
+	for _, ff := range f {
+		if c.StringWidth(ff) == w {
+			return ff, true
+		}
+	}
+	return f[0], true
+}
+
+// ReplaceFallbacks replaces every character in s that has a Fallback.
+func (c *Condition) ReplaceFallbacks(s string) string {
+	var b *strings.Builder
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		f, ok := c.Fallback(r)
+		switch {
+		case ok && b == nil:
+			b = new(strings.Builder)
+			b.Grow(len(s))
+			b.WriteString(s[:i])
+			b.WriteString(f)
+		case ok:
+			b.WriteString(f)
+		case b != nil:
+			b.WriteString(s[i : i+size])
+		}
+		i += size
+	}
+	if b == nil {
+		return s
+	}
+	return b.String()
+}
+
+// Fallback returns a replacement for r that displays the same on all
+// terminals.
+func Fallback(r rune) (string, bool) {
+	return DefaultCondition.Fallback(r)
+}
+
+// ReplaceFallbacks replaces every character in s that has a Fallback.
+func ReplaceFallbacks(s string) string {
+	return DefaultCondition.ReplaceFallbacks(s)
+}
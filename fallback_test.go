@@ -0,0 +1,50 @@
+package runewidth
+
+import (
+	"testing"
+)
+
+func TestFallback(t *testing.T) {
+	tests := []struct {
+		in   rune
+		ea   bool
+		want string
+		ok   bool
+	}{
+		{'a', false, "", false},
+		{'—', false, "-", true},
+		{'—', true, "--", true},
+		{'→', true, "->", true},
+		{'🇳', false, "N", true},
+		{'❤', false, ":heart:", true},
+	}
+
+	for _, tt := range tests {
+		have, ok := newCond(tt.ea).Fallback(tt.in)
+		if have != tt.want || ok != tt.ok {
+			t.Errorf("Fallback(%q) = (%q, %t), want (%q, %t) (EastAsianWidth=%t)",
+				tt.in, have, ok, tt.want, tt.ok, tt.ea)
+		}
+	}
+}
+
+func TestReplaceFallbacks(t *testing.T) {
+	tests := []struct {
+		in   string
+		ea   bool
+		want string
+	}{
+		{"", false, ""},
+		{"abc", false, "abc"},
+		{"a—b", false, "a-b"},
+		{"a—b", true, "a--b"},
+		{"🇳🇱 ❤ 世界", false, "NL :heart: 世界"},
+		{"\xffa—", false, "\xffa-"},
+	}
+
+	for _, tt := range tests {
+		if have := newCond(tt.ea).ReplaceFallbacks(tt.in); have != tt.want {
+			t.Errorf("ReplaceFallbacks(%q) = %q, want %q (EastAsianWidth=%t)", tt.in, have, tt.want, tt.ea)
+		}
+	}
+}
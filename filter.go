@@ -0,0 +1,120 @@
+package runewidth
+
+import "strings"
+
+// Filter finds text that isn't displayed, such as markup, so that it's not
+// measured.
+type Filter interface {
+	// Hidden returns the length in bytes of the text at the start of s that
+	// isn't displayed, or 0 if s starts with text that is displayed.
+	Hidden(s string) int
+}
+
+// FilterFunc is an adapter to use a function as a Filter.
+type FilterFunc func(s string) int
+
+// Hidden calls f(s).
+func (f FilterFunc) Hidden(s string) int { return f(s) }
+
+// ANSIFilter hides ANSI escape sequences.
+var ANSIFilter Filter = FilterFunc(escapeLen)
+
+// MarkupFilter returns a Filter that hides every occurrence of the markers,
+// such as "**" and "_" for Markdown emphasis, or "[b]" and "[/b]" for BBCode.
+func MarkupFilter(markers ...string) Filter {
+	return FilterFunc(func(s string) int {
+		for _, m := range markers {
+			if m != "" && strings.HasPrefix(s, m) {
+				return len(m)
+			}
+		}
+		return 0
+	})
+}
+
+// visible returns the text in s that's not hidden by any of the filters, and
+// the byte offset in s for every byte in it.
+func visible(s string, filters []Filter) (string, []int) {
+	var (
+		b    strings.Builder
+		offs = make([]int, 0, len(s))
+	)
+	b.Grow(len(s))
+outer:
+	for i := 0; i < len(s); {
+		for _, f := range filters {
+			if n := f.Hidden(s[i:]); n > 0 {
+				i += n
+				continue outer
+			}
+		}
+		b.WriteByte(s[i])
+		offs = append(offs, i)
+		i++
+	}
+	return b.String(), offs
+}
+
+// StringWidthFilter returns the number of cells in s, without the text that's
+// hidden by the filters.
+//
+// For example to measure Markdown text as it will be displayed, a filter can
+// hide the emphasis markers.
+func (c *Condition) StringWidthFilter(s string, filters ...Filter) int {
+	v, _ := visible(s, filters)
+	return c.StringWidth(v)
+}
+
+// TruncateFilter is like Truncate, but doesn't count the text that's hidden by
+// the filters. Hidden text is kept as-is up to where s is truncated.
+func (c *Condition) TruncateFilter(s string, w int, tail string, filters ...Filter) string {
+	v, offs := visible(s, filters)
+	if c.StringWidth(v) <= w {
+		return s
+	}
+	pos, tail, _, _ := c.truncateAt(v, w, tail)
+	if pos < len(offs) {
+		return s[:offs[pos]] + tail
+	}
+	return s + tail
+}
+
+// WrapFilter is like Wrap, but doesn't count the text that's hidden by the
+// filters. Hidden text is kept as-is.
+func (c *Condition) WrapFilter(s string, w int, filters ...Filter) string {
+	v, offs := visible(s, filters)
+	pos := c.WrapPositions(v, w)
+	if len(pos) == 0 {
+		return s
+	}
+	var (
+		b    strings.Builder
+		prev int
+	)
+	b.Grow(len(s) + len(pos))
+	for _, p := range pos {
+		b.WriteString(s[prev:offs[p]])
+		b.WriteByte('\n')
+		prev = offs[p]
+	}
+	b.WriteString(s[prev:])
+	return b.String()
+}
+
+// StringWidthFilter returns the number of cells in s, without the text that's
+// hidden by the filters.
+func StringWidthFilter(s string, filters ...Filter) int {
+	return DefaultCondition.StringWidthFilter(s, filters...)
+}
+
+// TruncateFilter is like Truncate, but doesn't count the text that's hidden by
+// the filters.
+func TruncateFilter(s string, w int, tail string, filters ...Filter) string {
+	return DefaultCondition.TruncateFilter(s, w, tail, filters...)
+}
+
+// WrapFilter is like Wrap, but doesn't count the text that's hidden by the
+// filters.
+func WrapFilter(s string, w int, filters ...Filter) string {
+	return DefaultCondition.WrapFilter(s, w, filters...)
+}
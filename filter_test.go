@@ -0,0 +1,75 @@
+package runewidth
+
+import (
+	"testing"
+)
+
+var (
+	emphasis = MarkupFilter("**", "_")
+	bbcode   = MarkupFilter("[b]", "[/b]")
+)
+
+func TestStringWidthFilter(t *testing.T) {
+	tests := []struct {
+		in      string
+		filters []Filter
+		want    int
+	}{
+		{"", nil, 0},
+		{"**bold**", nil, 8},
+		{"**bold**", []Filter{emphasis}, 4},
+		{"_世界_ [b]x[/b]", []Filter{emphasis}, 13},
+		{"_世界_ [b]x[/b]", []Filter{emphasis, bbcode}, 6},
+		{"\x1b[1mbold\x1b[0m", []Filter{ANSIFilter}, 4},
+		{"a\t**b**", []Filter{emphasis}, 9},
+	}
+
+	c := newCond(false)
+	for _, tt := range tests {
+		if have := c.StringWidthFilter(tt.in, tt.filters...); have != tt.want {
+			t.Errorf("StringWidthFilter(%q) = %d, want %d", tt.in, have, tt.want)
+		}
+	}
+}
+
+func TestTruncateFilter(t *testing.T) {
+	tests := []struct {
+		in      string
+		w       int
+		filters []Filter
+		want    string
+	}{
+		{"**bold**", 4, []Filter{emphasis}, "**bold**"},
+		{"**bold** text", 6, []Filter{emphasis}, "**bold** …"},
+		{"[b]世界[/b]です", 5, []Filter{bbcode}, "[b]世界[/b]…"},
+		{"\x1b[1mbold\x1b[0m text", 3, []Filter{ANSIFilter}, "\x1b[1mbo…"},
+	}
+
+	c := newCond(false)
+	for _, tt := range tests {
+		if have := c.TruncateFilter(tt.in, tt.w, "…", tt.filters...); have != tt.want {
+			t.Errorf("TruncateFilter(%q, %d)\nhave: %q\nwant: %q", tt.in, tt.w, have, tt.want)
+		}
+	}
+}
+
+func TestWrapFilter(t *testing.T) {
+	tests := []struct {
+		in      string
+		w       int
+		filters []Filter
+		want    string
+	}{
+		{"**bold**", 4, []Filter{emphasis}, "**bold**"},
+		{"**abc**def", 3, []Filter{emphasis}, "**abc**\ndef"},
+		{"_世界_です", 4, []Filter{emphasis}, "_世界_\nです"},
+		{"\x1b[1mabcdef\x1b[0m", 3, []Filter{ANSIFilter}, "\x1b[1mabc\ndef\x1b[0m"},
+	}
+
+	c := newCond(false)
+	for _, tt := range tests {
+		if have := c.WrapFilter(tt.in, tt.w, tt.filters...); have != tt.want {
+			t.Errorf("WrapFilter(%q, %d)\nhave: %q\nwant: %q", tt.in, tt.w, have, tt.want)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package runewidth
+
+// TableColumn is a column for FitColumns.
+type TableColumn struct {
+	// Header and the cells in the column; the column's natural width is the
+	// width of the widest of these.
+	Cells []string
+
+	// Minimum width the column can be shrunk to; values lower than 1 are
+	// treated as 1. The column is hidden if it doesn't fit at this width.
+	Min int
+
+	// Columns with a lower priority are shrunk and hidden first. Columns with
+	// the same priority are shrunk evenly, and hidden from right to left.
+	Priority int
+}
+
+// FitColumns calculates the widths of the columns of a table to fit in width
+// cells, with gutter cells between the columns, and returns the width of every
+// column. A width of 0 means the column is hidden.
+//
+// Columns get their natural width if everything fits. If it doesn't, columns
+// are shrunk towards their minimum width in order of priority, and if that's
+// still not enough columns are hidden in order of priority (after which the
+// remaining columns are laid out again). The cells can be shortened to the
+// column width with Truncate and padded with FillRight.
+func (c *Condition) FitColumns(cols []TableColumn, width, gutter int) []int {
+	var (
+		widths  = make([]int, len(cols))
+		mins    = make([]int, len(cols))
+		visible = 0
+	)
+	for i, col := range cols {
+		for _, s := range col.Cells {
+			if w := c.StringWidth(s); w > widths[i] {
+				widths[i] = w
+			}
+		}
+		mins[i] = col.Min
+		if mins[i] < 1 {
+			mins[i] = 1
+		}
+		if mins[i] > widths[i] {
+			mins[i] = widths[i]
+		}
+		if widths[i] > 0 {
+			visible++
+		}
+	}
+
+	// Hide columns until the rest fits at their minimum width.
+	for visible > 0 && sumVisible(mins, widths, gutter) > width {
+		hide := -1
+		for i := range cols {
+			if widths[i] > 0 && (hide == -1 || cols[i].Priority <= cols[hide].Priority) {
+				hide = i
+			}
+		}
+		widths[hide] = 0
+		visible--
+	}
+
+	// Shrink the widest of the lowest priority columns one cell at a time.
+	for over := sumVisible(widths, widths, gutter) - width; over > 0; over-- {
+		shrink := -1
+		for i := range cols {
+			if widths[i] <= mins[i] {
+				continue
+			}
+			if shrink == -1 || cols[i].Priority < cols[shrink].Priority ||
+				cols[i].Priority == cols[shrink].Priority && widths[i] >= widths[shrink] {
+				shrink = i
+			}
+		}
+		widths[shrink]--
+	}
+	return widths
+}
+
+// Breakpoint is a layout for FitBreakpoints.
+type Breakpoint struct {
+	MinWidth int   // Smallest width to use this layout for.
+	Columns  []int // Indexes of the columns to show.
+}
+
+// FitBreakpoints selects the breakpoint for width and calculates the widths of
+// its columns to fit in width cells, like FitColumns. This allows showing
+// fewer or different columns on narrow terminals, like web pages do with CSS
+// media queries.
+//
+// The breakpoint with the largest MinWidth that's not larger than width is
+// used, or the one with the smallest MinWidth if width is smaller than all of
+// them. It returns the index of the breakpoint and the width of every column
+// in cols, where columns not in the breakpoint have a width of 0. All columns
+// are used if there are no breakpoints, in which case the index is -1.
+func (c *Condition) FitBreakpoints(cols []TableColumn, breakpoints []Breakpoint, width, gutter int) (int, []int) {
+	if len(breakpoints) == 0 {
+		return -1, c.FitColumns(cols, width, gutter)
+	}
+
+	bp := 0
+	for i, b := range breakpoints {
+		fits, bpFits := b.MinWidth <= width, breakpoints[bp].MinWidth <= width
+		switch {
+		case fits && (!bpFits || b.MinWidth > breakpoints[bp].MinWidth):
+			bp = i
+		case !fits && !bpFits && b.MinWidth < breakpoints[bp].MinWidth:
+			bp = i
+		}
+	}
+
+	show := make([]TableColumn, 0, len(breakpoints[bp].Columns))
+	for _, i := range breakpoints[bp].Columns {
+		show = append(show, cols[i])
+	}
+	widths := make([]int, len(cols))
+	for i, w := range c.FitColumns(show, width, gutter) {
+		widths[breakpoints[bp].Columns[i]] = w
+	}
+	return bp, widths
+}
+
+// sumVisible returns the total width of the columns with a width in visible,
+// using the widths from widths.
+func sumVisible(widths, visible []int, gutter int) int {
+	t, n := 0, 0
+	for i, w := range widths {
+		if visible[i] > 0 {
+			t += w
+			n++
+		}
+	}
+	if n > 0 {
+		t += gutter * (n - 1)
+	}
+	return t
+}
+
+// FitColumns calculates the widths of the columns of a table to fit in width
+// cells.
+func FitColumns(cols []TableColumn, width, gutter int) []int {
+	return DefaultCondition.FitColumns(cols, width, gutter)
+}
+
+// FitBreakpoints selects the breakpoint for width and calculates the widths of
+// its columns to fit in width cells.
+func FitBreakpoints(cols []TableColumn, breakpoints []Breakpoint, width, gutter int) (int, []int) {
+	return DefaultCondition.FitBreakpoints(cols, breakpoints, width, gutter)
+}
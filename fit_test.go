@@ -0,0 +1,90 @@
+package runewidth
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFitColumns(t *testing.T) {
+	var (
+		name = TableColumn{Cells: []string{"NAME", "runewidth", "go"}, Min: 4, Priority: 2}
+		desc = TableColumn{Cells: []string{"DESCRIPTION", "Width of characters"}, Min: 8, Priority: 1}
+		size = TableColumn{Cells: []string{"SIZE", "12K"}, Priority: 0}
+		cjk  = TableColumn{Cells: []string{"名前", "日本語の名前"}, Min: 4, Priority: 1}
+	)
+
+	tests := []struct {
+		cols   []TableColumn
+		width  int
+		gutter int
+		want   []int
+	}{
+		{nil, 80, 1, []int{}},
+		{[]TableColumn{name, desc, size}, 80, 2, []int{9, 19, 4}},
+		{[]TableColumn{name, desc, size}, 36, 2, []int{9, 19, 4}},
+		// Lowest priority first: size can shrink to 1, then desc to 8.
+		{[]TableColumn{name, desc, size}, 34, 2, []int{9, 19, 2}},
+		{[]TableColumn{name, desc, size}, 30, 2, []int{9, 16, 1}},
+		{[]TableColumn{name, desc, size}, 22, 2, []int{9, 8, 1}},
+		{[]TableColumn{name, desc, size}, 17, 2, []int{4, 8, 1}},
+		// Hide size, which frees up room for name again.
+		{[]TableColumn{name, desc, size}, 16, 2, []int{6, 8, 0}},
+		{[]TableColumn{name, desc, size}, 12, 2, []int{9, 0, 0}},
+		{[]TableColumn{name, desc, size}, 4, 2, []int{4, 0, 0}},
+		{[]TableColumn{name, desc, size}, 3, 2, []int{0, 0, 0}},
+		// Same priority: shrink the widest, and hide from the right.
+		{[]TableColumn{desc, cjk}, 20, 1, []int{10, 9}},
+		{[]TableColumn{desc, cjk}, 12, 1, []int{12, 0}},
+		// Empty column takes no gutter.
+		{[]TableColumn{name, {}, size}, 14, 1, []int{9, 0, 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run("", func(t *testing.T) {
+			have := FitColumns(tt.cols, tt.width, tt.gutter)
+			if !reflect.DeepEqual(have, tt.want) {
+				t.Errorf("width %d: have %v, want %v", tt.width, have, tt.want)
+			}
+		})
+	}
+}
+
+func TestFitBreakpoints(t *testing.T) {
+	cols := []TableColumn{
+		{Cells: []string{"ID", "1234"}},
+		{Cells: []string{"名前", "ウェブサーバー"}, Min: 6},
+		{Cells: []string{"STATUS", "Up 3 hours"}, Min: 4},
+		{Cells: []string{"PORTS", "0.0.0.0:80->80/tcp"}, Min: 5},
+	}
+	bps := []Breakpoint{
+		{80, []int{0, 1, 2, 3}},
+		{40, []int{0, 1, 2}},
+		{20, []int{1, 2}},
+	}
+
+	tests := []struct {
+		bps    []Breakpoint
+		width  int
+		wantBP int
+		want   []int
+	}{
+		{nil, 80, -1, []int{4, 14, 10, 18}},
+		{nil, 30, -1, []int{4, 8, 8, 7}},
+		{bps, 100, 0, []int{4, 14, 10, 18}},
+		{bps, 80, 0, []int{4, 14, 10, 18}},
+		{bps, 79, 1, []int{4, 14, 10, 0}},
+		{bps, 30, 2, []int{0, 14, 10, 0}},
+		{bps, 12, 2, []int{0, 6, 5, 0}},
+		{bps, 5, 2, []int{0, 0, 0, 0}},
+		{[]Breakpoint{{40, []int{0}}, {20, []int{3}}}, 10, 1, []int{0, 0, 0, 10}},
+	}
+
+	for _, tt := range tests {
+		t.Run("", func(t *testing.T) {
+			bp, have := FitBreakpoints(cols, tt.bps, tt.width, 1)
+			if bp != tt.wantBP || !reflect.DeepEqual(have, tt.want) {
+				t.Errorf("width %d:\nhave: %d %v\nwant: %d %v", tt.width, bp, have, tt.wantBP, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+//go:build go1.18
+
+package runewidth
+
+import (
+	"strings"
+	"testing"
+)
+
+var fuzzSeeds = []string{"", "abc", "あいうえお", "áb⃝", "🇳🇱❤️", "a\nb\r\nc", "\xff\xfe", "\x1b[31mred\x1b[0m", "\t世界\t"}
+
+func FuzzTruncate(f *testing.F) {
+	for _, s := range fuzzSeeds {
+		f.Add(s, 5, "…")
+		f.Add(s, 0, "...")
+		f.Add(s, 1, "あ")
+	}
+	f.Fuzz(func(t *testing.T, s string, w int, tail string) {
+		c := newCond(false)
+		have := c.Truncate(s, w, tail)
+		if w >= 0 && c.StringWidth(have) > w {
+			t.Fatalf("Truncate(%q, %d, %q) = %q; too wide", s, w, tail, have)
+		}
+		if c.StringWidth(s) <= w && have != s {
+			t.Fatalf("Truncate(%q, %d, %q) = %q; modified", s, w, tail, have)
+		}
+		if noTail := c.Truncate(s, w, ""); !strings.HasPrefix(s, noTail) {
+			t.Fatalf("Truncate(%q, %d, \"\") = %q; not a prefix", s, w, noTail)
+		}
+	})
+}
+
+func FuzzWrap(f *testing.F) {
+	for _, s := range fuzzSeeds {
+		f.Add(s, 5)
+		f.Add(s, 1)
+		f.Add(s, 0)
+	}
+	f.Fuzz(func(t *testing.T, s string, w int) {
+		c := newCond(false)
+		have := c.Wrap(s, w)
+		if strings.ReplaceAll(have, "\n", "") != strings.ReplaceAll(s, "\n", "") {
+			t.Fatalf("Wrap(%q, %d) = %q; content changed", s, w, have)
+		}
+		if w <= 0 {
+			if have != s {
+				t.Fatalf("Wrap(%q, %d) = %q; modified", s, w, have)
+			}
+			return
+		}
+		for _, l := range strings.Split(have, "\n") {
+			if lw := c.StringWidth(l); lw > w {
+				// Only allowed if it's a single character that's wider than w.
+				var visible int
+				for i := 0; i < len(l); {
+					n, cw := c.cluster(l[i:])
+					if cw > 0 {
+						visible++
+					}
+					i += n
+				}
+				if visible > 1 {
+					t.Fatalf("Wrap(%q, %d) = %q; line %q too wide", s, w, have, l)
+				}
+			}
+		}
+	})
+}
+
+func FuzzFill(f *testing.F) {
+	for _, s := range fuzzSeeds {
+		f.Add(s, 10)
+	}
+	f.Fuzz(func(t *testing.T, s string, w int) {
+		if w > 1000 {
+			return
+		}
+		c := newCond(false)
+		left, right := c.FillLeft(s, w), c.FillRight(s, w)
+		if !strings.HasSuffix(left, s) || !strings.HasPrefix(right, s) {
+			t.Fatalf("Fill(%q, %d) = %q, %q", s, w, left, right)
+		}
+		sw := c.StringWidth(s)
+		if lw := c.StringWidth(left); lw != sw && lw != w {
+			// Padding before a tab may not be able to reach w exactly, but
+			// another space must make it too wide.
+			if !strings.Contains(s, "\t") || lw > w || c.StringWidth(" "+left) <= w {
+				t.Fatalf("FillLeft(%q, %d) = %q; wrong width", s, w, left)
+			}
+		}
+		if rw := c.StringWidth(right); rw != sw && rw != w {
+			t.Fatalf("FillRight(%q, %d) = %q; wrong width", s, w, right)
+		}
+	})
+}
+
+func FuzzClip(f *testing.F) {
+	for _, s := range fuzzSeeds {
+		f.Add(s, 1, 4)
+	}
+	f.Fuzz(func(t *testing.T, s string, from, to int) {
+		c := newCond(false)
+		for _, have := range []string{c.Clip(s, from, to), c.ClipANSI(s, from, to)} {
+			if w := c.StringWidth(have); to >= from && w > to-from && !strings.Contains(have, "\x1b") {
+				t.Fatalf("Clip(%q, %d, %d) = %q; too wide", s, from, to, have)
+			}
+		}
+	})
+}
+
+func FuzzTruncateAround(f *testing.F) {
+	for _, s := range fuzzSeeds {
+		f.Add(s, 4, 2, "…")
+	}
+	f.Fuzz(func(t *testing.T, s string, w, focus int, tail string) {
+		c := newCond(false)
+		have := c.TruncateAround(s, w, focus, tail)
+		if w >= 0 && c.StringWidth(have) > w {
+			t.Fatalf("TruncateAround(%q, %d, %d, %q) = %q; too wide", s, w, focus, tail, have)
+		}
+	})
+}
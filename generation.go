@@ -0,0 +1,55 @@
+package runewidth
+
+import "hash/fnv"
+
+// lutKey are the settings that affect the values in the LUT. This uses the
+// fields as they're set rather than the values derived from them, as it's
+// compared on every RuneWidth call with a LUT.
+type lutKey struct {
+	eastAsian, strictEmoji bool
+	locale, version        string
+	ambiguousWidth         int
+	controlWidth           int
+	privateUseWidth        int
+	nerdFonts              bool
+	wideSymbols            bool
+	compat                 Compat
+	gen                    uint32
+}
+
+func (c *Condition) key() lutKey {
+	return lutKey{c.EastAsianWidth, c.StrictEmojiNeutral, c.AmbiguousLocale, c.UnicodeVersion, c.AmbiguousWidth, c.ControlWidth, c.PrivateUseWidth, c.NerdFonts, c.WideSymbols, c.Compat, c.gen}
+}
+
+// lutValid reports if the LUT was created with the current settings. This is
+// the same as c.lutKey == c.key(), but avoids copying the key.
+func (c *Condition) lutValid() bool {
+	k := &c.lutKey
+	return k.gen == c.gen && k.eastAsian == c.EastAsianWidth && k.strictEmoji == c.StrictEmojiNeutral &&
+		k.ambiguousWidth == c.AmbiguousWidth && k.controlWidth == c.ControlWidth &&
+		k.privateUseWidth == c.PrivateUseWidth && k.nerdFonts == c.NerdFonts &&
+		k.wideSymbols == c.WideSymbols && k.compat == c.Compat &&
+		sameString(k.locale, c.AmbiguousLocale) && sameString(k.version, c.UnicodeVersion)
+}
+
+// invalidate marks the LUT as stale and changes the Generation. This should be
+// called by anything that changes widths in a way that's not reflected in the
+// exported fields.
+func (c *Condition) invalidate() {
+	c.gen++
+	c.combinedLut = nil
+}
+
+// Generation returns a number that changes whenever a setting in c that
+// affects the results is changed, so that caches of computed widths can detect
+// that they're stale. Changing a setting back to the previous value may result
+// in the previous generation.
+//
+// The lookup table created with CreateLUT is not used once c is changed, until
+// CreateLUT is called again. Changes to ImageWidth and Metrics are not
+// detected.
+func (c *Condition) Generation() uint64 {
+	h := fnv.New32a()
+	h.Write([]byte(c.Profile().String()))
+	return uint64(c.gen)<<32 | uint64(h.Sum32())
+}
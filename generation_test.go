@@ -0,0 +1,59 @@
+package runewidth
+
+import "testing"
+
+func TestGeneration(t *testing.T) {
+	c := newCond(false)
+	g := c.Generation()
+	if c.Generation() != g {
+		t.Fatal("generation changed without changes")
+	}
+
+	c.WideEnclosing = true
+	g2 := c.Generation()
+	if g2 == g {
+		t.Error("generation not changed after setting WideEnclosing")
+	}
+	c.WideEnclosing = false
+	if c.Generation() != g {
+		t.Error("generation not restored")
+	}
+
+	c.invalidate()
+	if c.Generation() == g {
+		t.Error("generation not changed after invalidate")
+	}
+}
+
+func TestStaleLUT(t *testing.T) {
+	c := newCond(false)
+	c.CreateLUT()
+	if w := c.RuneWidth('★'); w != 1 {
+		t.Fatalf("RuneWidth('★') = %d, want 1", w)
+	}
+
+	// Must not use the old LUT.
+	c.EastAsianWidth = true
+	if w := c.RuneWidth('★'); w != 2 {
+		t.Errorf("RuneWidth('★') = %d, want 2", w)
+	}
+	c.CreateLUT()
+	if w := c.RuneWidth('★'); w != 2 {
+		t.Errorf("RuneWidth('★') = %d, want 2", w)
+	}
+
+	c.invalidate()
+	if len(c.combinedLut) > 0 {
+		t.Error("LUT not removed")
+	}
+
+	c = NewCondition()
+	c.CreateLUT()
+	c.UnicodeVersion = "9.0.0"
+	if w := c.RuneWidth(0x1F6DC); w != 1 {
+		t.Errorf("RuneWidth(U+1F6DC) = %d, want 1", w)
+	}
+	if len(c.combinedLut) > 0 {
+		t.Error("stale LUT not removed")
+	}
+}
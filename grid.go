@@ -0,0 +1,59 @@
+package runewidth
+
+// Grid is the layout of items in a grid of fixed-width cells; see Flow.
+type Grid struct {
+	CellWidth int // Width of every cell, excluding the gutter.
+	Gutter    int // Cells between columns.
+	Cols      int // Number of columns.
+	Rows      int // Number of rows.
+}
+
+// Pos returns the row and column of the item at index i.
+func (g Grid) Pos(i int) (row, col int) {
+	if g.Cols == 0 {
+		return 0, 0
+	}
+	return i / g.Cols, i % g.Cols
+}
+
+// Offset returns the cell offset of the column col from the start of a row.
+func (g Grid) Offset(col int) int {
+	return col * (g.CellWidth + g.Gutter)
+}
+
+// Flow lays out items in rows of fixed-width cells within width cells, with
+// gutter cells between the columns. Items are ordered in rows first (left to
+// right, then top to bottom).
+//
+// The cell width is the width of the widest item. There is always at least one
+// column, even if the widest item doesn't fit.
+func (c *Condition) Flow(items []string, width, gutter int) Grid {
+	g := Grid{Gutter: gutter}
+	if len(items) == 0 {
+		return g
+	}
+	for _, s := range items {
+		if w := c.StringWidth(s); w > g.CellWidth {
+			g.CellWidth = w
+		}
+	}
+
+	g.Cols = 1
+	if cw := g.CellWidth + gutter; cw > 0 {
+		if n := (width + gutter) / cw; n > 1 {
+			g.Cols = n
+		}
+	} else {
+		g.Cols = len(items)
+	}
+	if g.Cols > len(items) {
+		g.Cols = len(items)
+	}
+	g.Rows = (len(items) + g.Cols - 1) / g.Cols
+	return g
+}
+
+// Flow lays out items in rows of fixed-width cells within width cells.
+func Flow(items []string, width, gutter int) Grid {
+	return DefaultCondition.Flow(items, width, gutter)
+}
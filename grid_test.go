@@ -0,0 +1,44 @@
+package runewidth
+
+import "testing"
+
+func TestFlow(t *testing.T) {
+	tests := []struct {
+		in     []string
+		width  int
+		gutter int
+		want   Grid
+	}{
+		{nil, 80, 1, Grid{Gutter: 1}},
+		{[]string{"a", "bb", "c"}, 80, 1, Grid{CellWidth: 2, Gutter: 1, Cols: 3, Rows: 1}},
+		{[]string{"a", "bb", "c"}, 5, 1, Grid{CellWidth: 2, Gutter: 1, Cols: 2, Rows: 2}},
+		{[]string{"a", "bb", "c"}, 4, 1, Grid{CellWidth: 2, Gutter: 1, Cols: 1, Rows: 3}},
+		{[]string{"東京", "大阪", "名古屋", "札幌"}, 14, 2, Grid{CellWidth: 6, Gutter: 2, Cols: 2, Rows: 2}},
+		{[]string{"long item"}, 3, 2, Grid{CellWidth: 9, Gutter: 2, Cols: 1, Rows: 1}},
+		{[]string{"", ""}, 3, 0, Grid{Cols: 2, Rows: 1}},
+	}
+
+	c := newCond(false)
+	for _, tt := range tests {
+		if have := c.Flow(tt.in, tt.width, tt.gutter); have != tt.want {
+			t.Errorf("Flow(%q, %d, %d) = %+v, want %+v", tt.in, tt.width, tt.gutter, have, tt.want)
+		}
+	}
+}
+
+func TestGridPos(t *testing.T) {
+	g := Grid{CellWidth: 4, Gutter: 2, Cols: 3, Rows: 2}
+	tests := []struct{ i, row, col, off int }{
+		{0, 0, 0, 0},
+		{2, 0, 2, 12},
+		{3, 1, 0, 0},
+		{4, 1, 1, 6},
+	}
+	for _, tt := range tests {
+		row, col := g.Pos(tt.i)
+		if row != tt.row || col != tt.col || g.Offset(col) != tt.off {
+			t.Errorf("Pos(%d) = (%d, %d) at %d, want (%d, %d) at %d",
+				tt.i, row, col, g.Offset(col), tt.row, tt.col, tt.off)
+		}
+	}
+}
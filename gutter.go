@@ -0,0 +1,48 @@
+package runewidth
+
+import (
+	"strconv"
+	"strings"
+)
+
+// NumberLines prefixes every line in s with its line number, starting at first.
+//
+// The numbers are right-aligned in a gutter that's wide enough for the first
+// and last line numbers, followed by a space. first may be negative, for
+// example for relative line numbers. Lines are truncated to the remaining w
+// cells (appending tail), and this remaining content width is returned as
+// well. Line breaks are kept as-is, and a line break at the end of s doesn't
+// start a new line.
+//
+//	NumberLines("a\nb\n", 9, 80, "…") // " 9 a\n10 b\n", 77
+func (c *Condition) NumberLines(s string, first, w int, tail string) (string, int) {
+	var (
+		lines  = c.lines(s)
+		digits = len(strconv.Itoa(first + len(lines) - 1))
+		cw     int
+		b      strings.Builder
+	)
+	if d := len(strconv.Itoa(first)); d > digits {
+		digits = d
+	}
+	cw = w - digits - 1
+	if cw < 0 {
+		cw = 0
+	}
+	b.Grow(len(s) + len(lines)*(digits+1))
+	for i, l := range lines {
+		n := strconv.Itoa(first + i)
+		b.WriteString(strings.Repeat(" ", digits-len(n)))
+		b.WriteString(n)
+		b.WriteByte(' ')
+		t := c.trimBreak(l)
+		b.WriteString(c.Truncate(t, cw, tail))
+		b.WriteString(l[len(t):])
+	}
+	return b.String(), cw
+}
+
+// NumberLines prefixes every line in s with its line number, starting at first.
+func NumberLines(s string, first, w int, tail string) (string, int) {
+	return DefaultCondition.NumberLines(s, first, w, tail)
+}
@@ -0,0 +1,31 @@
+package runewidth
+
+import "testing"
+
+func TestNumberLines(t *testing.T) {
+	tests := []struct {
+		in     string
+		first  int
+		w      int
+		want   string
+		wantCW int
+	}{
+		{"", 1, 80, "", 78},
+		{"a", 1, 80, "1 a", 78},
+		{"a\nb\n", 9, 80, " 9 a\n10 b\n", 77},
+		{"a\r\n\nb", 1, 80, "1 a\r\n2 \n3 b", 78},
+		{"abcdef\n東京大阪", 1, 6, "1 abc…\n2 東…", 4},
+		{"abc", 1, 1, "1 ", 0},
+		{"a\nb\nc", -10, 20, "-10 a\n -9 b\n -8 c", 16},
+		{"a\nb\nc", -1, 20, "-1 a\n 0 b\n 1 c", 17},
+	}
+
+	c := newCond(false)
+	for _, tt := range tests {
+		have, cw := c.NumberLines(tt.in, tt.first, tt.w, "…")
+		if have != tt.want || cw != tt.wantCW {
+			t.Errorf("NumberLines(%q, %d, %d) = (%q, %d), want (%q, %d)",
+				tt.in, tt.first, tt.w, have, cw, tt.want, tt.wantCW)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package runewidth
+
+import (
+	"unicode/utf8"
+)
+
+// VisibleHash returns a hash of the visible content of s.
+//
+// ANSI escape sequences, control characters, and other non-printable
+// characters such as zero-width spaces are ignored, so two strings with the
+// same hash display the same text (but may have different colours or
+// attributes).
+//
+// This uses 64-bit FNV-1a; it's not a cryptographic hash.
+func VisibleHash(s string) uint64 {
+	const (
+		offset = 14695981039346656037
+		prime  = 1099511628211
+	)
+	h := uint64(offset)
+	for i := 0; i < len(s); {
+		if n := escapeLen(s[i:]); n > 0 {
+			i += n
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if !inTable(r, nonprint) {
+			for j := i; j < i+size; j++ {
+				h ^= uint64(s[j])
+				h *= prime
+			}
+		}
+		i += size
+	}
+	return h
+}
@@ -0,0 +1,31 @@
+package runewidth
+
+import (
+	"testing"
+)
+
+func TestVisibleHash(t *testing.T) {
+	tests := []struct {
+		a, b string
+		same bool
+	}{
+		{"", "", true},
+		{"abc", "abc", true},
+		{"abc", "abd", false},
+		{"abc", "\x1b[31mabc\x1b[0m", true},
+		{"abc", "a\u200bb\ufeffc", true},
+		{"abc", "a\x07bc\r", true},
+		{"é", "e", false},
+		{"a b", "ab", false},
+	}
+
+	for _, tt := range tests {
+		if same := VisibleHash(tt.a) == VisibleHash(tt.b); same != tt.same {
+			t.Errorf("VisibleHash(%q) == VisibleHash(%q): %t, want %t", tt.a, tt.b, same, tt.same)
+		}
+	}
+
+	if have, want := VisibleHash(""), uint64(14695981039346656037); have != want {
+		t.Errorf("VisibleHash(\"\") = %d, want %d", have, want)
+	}
+}
@@ -0,0 +1,119 @@
+package runewidth
+
+import (
+	"strings"
+)
+
+// Indent adds prefix to the start of every non-empty line in s.
+//
+// It also returns the width of the widest line in the result, so callers can
+// check if it still fits.
+func (c *Condition) Indent(s, prefix string) (string, int) {
+	var (
+		b     strings.Builder
+		max   int
+		pw    = c.StringWidth(prefix)
+		lines = c.lines(s)
+	)
+	b.Grow(len(s) + len(lines)*len(prefix))
+	for _, l := range lines {
+		if c.trimBreak(l) != "" {
+			b.WriteString(prefix)
+			if w := pw + c.StringWidth(l); w > max {
+				max = w
+			}
+		}
+		b.WriteString(l)
+	}
+	return b.String(), max
+}
+
+// Dedent removes the common leading white space from every line in s.
+//
+// Tabs are expanded to the next tab stop when determining the
+// indentation; if a tab is only partly removed the remaining columns are
+// replaced with spaces. Lines with only white space are ignored for
+// determining the common indentation.
+//
+// It also returns the width of the widest line in the result.
+func (c *Condition) Dedent(s string) (string, int) {
+	lines := c.lines(s)
+
+	common := -1
+	for _, l := range lines {
+		if strings.TrimLeft(c.trimBreak(l), " \t") == "" {
+			continue
+		}
+		if n := c.indentColumns(l); common == -1 || n < common {
+			common = n
+		}
+	}
+	if common <= 0 {
+		max := 0
+		for _, l := range lines {
+			if w := c.StringWidth(l); w > max {
+				max = w
+			}
+		}
+		return s, max
+	}
+
+	var (
+		b   strings.Builder
+		max int
+	)
+	b.Grow(len(s))
+	for _, l := range lines {
+		l = c.removeIndent(l, common)
+		if w := c.StringWidth(l); w > max {
+			max = w
+		}
+		b.WriteString(l)
+	}
+	return b.String(), max
+}
+
+// indentColumns returns the number of columns of the leading spaces and tabs.
+func (c *Condition) indentColumns(l string) int {
+	col := 0
+	for i := 0; i < len(l); i++ {
+		switch l[i] {
+		case ' ':
+			col++
+		case '\t':
+			col += c.tabStop('\t', col, 0)
+		default:
+			return col
+		}
+	}
+	return col
+}
+
+// removeIndent removes n columns of leading spaces and tabs from l.
+func (c *Condition) removeIndent(l string, n int) string {
+	col := 0
+	for i := 0; i < len(l); i++ {
+		switch l[i] {
+		case ' ':
+			col++
+		case '\t':
+			col += c.tabStop('\t', col, 0)
+		default:
+			return l[i:]
+		}
+		if col >= n {
+			return strings.Repeat(" ", col-n) + l[i+1:]
+		}
+	}
+	return ""
+}
+
+// Indent adds prefix to the start of every non-empty line in s.
+func Indent(s, prefix string) (string, int) {
+	return DefaultCondition.Indent(s, prefix)
+}
+
+// Dedent removes the common leading white space from every line in s.
+func Dedent(s string) (string, int) {
+	return DefaultCondition.Dedent(s)
+}
@@ -0,0 +1,60 @@
+package runewidth
+
+import (
+	"testing"
+)
+
+func TestIndent(t *testing.T) {
+	tests := []struct {
+		in, prefix string
+		want       string
+		wantW      int
+	}{
+		{"", "  ", "", 0},
+		{"a", "  ", "  a", 3},
+		{"a\n\nbc\n", "> ", "> a\n\n> bc\n", 4},
+		{"世界\nx", "│ ", "│ 世界\n│ x", 6},
+	}
+
+	c := newCond(false)
+	for _, tt := range tests {
+		have, w := c.Indent(tt.in, tt.prefix)
+		if have != tt.want || w != tt.wantW {
+			t.Errorf("Indent(%q, %q)\nhave: %q %d\nwant: %q %d", tt.in, tt.prefix, have, w, tt.want, tt.wantW)
+		}
+	}
+}
+
+func TestDedent(t *testing.T) {
+	tests := []struct {
+		in    string
+		want  string
+		wantW int
+	}{
+		{"", "", 0},
+		{"abc", "abc", 3},
+		{"  a\n    b\n", "a\n  b\n", 3},
+		{"  a\n\n    b", "a\n\n  b", 3},
+		{"  a\n \n    b", "a\n\n  b", 3},
+		{"\ta\n\t\tb", "a\n\tb", 9},
+		{"\ta\n    世界", "    a\n世界", 5},
+		{"  \tx\n        y", "x\ny", 1},
+	}
+
+	c := newCond(false)
+	for _, tt := range tests {
+		have, w := c.Dedent(tt.in)
+		if have != tt.want || w != tt.wantW {
+			t.Errorf("Dedent(%q)\nhave: %q %d\nwant: %q %d", tt.in, have, w, tt.want, tt.wantW)
+		}
+	}
+}
+
+func TestDedentCR(t *testing.T) {
+	c := newCond(false)
+	c.Newlines = NewlineCR
+	have, w := c.Dedent("  a\r\n  \r\n    b")
+	if want := "a\r\n\r\n  b"; have != want || w != 3 {
+		t.Errorf("\nhave: %q %d\nwant: %q %d", have, w, want, 3)
+	}
+}
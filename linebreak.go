@@ -0,0 +1,79 @@
+package runewidth
+
+import (
+	"strings"
+	"unicode"
+)
+
+// lbClass is a simplified line breaking class from UAX #14.
+type lbClass uint8
+
+const (
+	lbAL lbClass = iota // Alphabetic and everything else.
+	lbID                // Ideographic: break before and after.
+	lbOP                // Opening punctuation: no break after.
+	lbCL                // Closing punctuation: no break before.
+	lbNS                // Nonstarters such as small kana: no break before.
+	lbHY                // Hyphen: break after.
+	lbGL                // Non-breaking ("glue"): no break before or after.
+	lbNU                // Numeric.
+)
+
+// closing are characters that can't start a line, in addition to the Pe
+// category. This is the CL, CP, EX, and IS classes from UAX #14.
+const closing = "!,.:;?、。，．！？：；｡､・…‥"
+
+// nonstarters are characters that can't start a line in strict Japanese line
+// breaking (the NS and CJ classes from UAX #14).
+const nonstarters = "ぁぃぅぇぉっゃゅょゎゕゖァィゥェォッャュョヮヵヶㇰㇱㇲㇳㇴㇵㇶㇷㇸㇹㇺㇻㇼㇽㇾㇿーゝゞヽヾ々〻〜゠"
+
+// lineBreakClass returns the line breaking class of r.
+//
+// This is a simplification of UAX #14 that doesn't use the Unicode data
+// tables; it's enough to handle CJK text, punctuation, and hyphens.
+func lineBreakClass(r rune) lbClass {
+	switch {
+	case r < 0x80 && (r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z'):
+		return lbAL
+	case r >= '0' && r <= '9':
+		return lbNU
+	case r == '-' || r == 0x2010 || r == 0x2012 || r == 0x2013:
+		return lbHY
+	case r == 0xA0 || r == 0x2007 || r == 0x202F || r == 0x2060 || r == 0xFEFF:
+		return lbGL
+	case strings.ContainsRune(closing, r) || unicode.Is(unicode.Pe, r):
+		return lbCL
+	case strings.ContainsRune(nonstarters, r):
+		return lbNS
+	case unicode.Is(unicode.Ps, r):
+		return lbOP
+	case inTable(r, doublewidth) && !unicode.IsPunct(r):
+		return lbID
+	}
+	return lbAL
+}
+
+// canBreak reports if a line can be broken between two characters with the
+// classes prev and next, if there's no white space between them.
+func canBreak(prev, next lbClass) bool {
+	switch {
+	case kinsoku(prev, next) || next == lbGL || prev == lbGL:
+		return false
+	case prev == lbID || next == lbID:
+		return true
+	case prev == lbHY:
+		return next != lbNU
+	}
+	return false
+}
+
+// kinsoku reports if a line can't be broken between two characters with the
+// classes prev and next according to the Japanese line breaking rules.
+func kinsoku(prev, next lbClass) bool {
+	return next == lbCL || next == lbNS || prev == lbOP
+}
+
+// isCJKPunct reports if r is CJK or fullwidth punctuation.
+func isCJKPunct(r rune) bool {
+	return r >= 0x3000 && r <= 0x303F || r >= 0xFF01 && r <= 0xFF65 && unicode.IsPunct(r)
+}
@@ -0,0 +1,122 @@
+package runewidth
+
+import (
+	"strings"
+)
+
+// NewlinePolicy sets which characters are treated as line breaks.
+type NewlinePolicy uint8
+
+const (
+	// NewlineCR treats a lone "\r" as a line break. "\r\n" is always a
+	// single line break.
+	NewlineCR NewlinePolicy = 1 << iota
+
+	// NewlineUnicode treats U+2028 LINE SEPARATOR and U+2029 PARAGRAPH
+	// SEPARATOR as line breaks, instead of zero-width characters.
+	NewlineUnicode
+
+	// NewlineCRReset treats a "\r" as moving back to the start of the line,
+	// like a terminal does. Text after it overwrites earlier text, so the
+	// width of a line is the width of its widest "\r"-separated part rather
+	// than the total. This is useful for measuring progress-bar style output.
+	//
+	// This has no effect if NewlineCR is also set, as the "\r" will be a
+	// line break.
+	NewlineCRReset
+)
+
+// lineBreak returns the length of the line break at the start of s, or 0 if s
+// doesn't start with a line break.
+func (c *Condition) lineBreak(s string) int {
+	if len(s) == 0 {
+		return 0
+	}
+	switch s[0] {
+	case '\n':
+		return 1
+	case '\r':
+		if len(s) > 1 && s[1] == '\n' {
+			return 2
+		}
+		if c.Newlines&NewlineCR != 0 {
+			return 1
+		}
+	case 0xe2:
+		if c.Newlines&NewlineUnicode != 0 && (strings.HasPrefix(s, "\u2028") || strings.HasPrefix(s, "\u2029")) {
+			return 3
+		}
+	}
+	return 0
+}
+
+// nextLine returns the length of the first line in s and the length of the
+// line break that ends it, which is 0 for the last line.
+func (c *Condition) nextLine(s string) (n, brk int) {
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\n' && s[i] != '\r' && s[i] != 0xe2 {
+			continue
+		}
+		if b := c.lineBreak(s[i:]); b > 0 {
+			return i, b
+		}
+	}
+	return len(s), 0
+}
+
+// lines splits s in lines; every line includes the line break that ends it.
+func (c *Condition) lines(s string) []string {
+	var lines []string
+	for len(s) > 0 {
+		n, brk := c.nextLine(s)
+		lines = append(lines, s[:n+brk])
+		s = s[n+brk:]
+	}
+	return lines
+}
+
+// trimBreak removes the line break from the end of a line returned by lines().
+func (c *Condition) trimBreak(l string) string {
+	n, _ := c.nextLine(l)
+	return l[:n]
+}
+
+// MeasureBlock returns the width of the widest line in s and the number of
+// lines. A line break at the end of s doesn't start a new line.
+func (c *Condition) MeasureBlock(s string) (width, height int) {
+	for len(s) > 0 {
+		n, brk := c.nextLine(s)
+		if w := c.StringWidth(s[:n]); w > width {
+			width = w
+		}
+		height++
+		s = s[n+brk:]
+	}
+	return width, height
+}
+
+// TruncateLines truncates every line in s to w cells, appending tail to
+// lines that were truncated. Line breaks are kept as-is.
+func (c *Condition) TruncateLines(s string, w int, tail string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for len(s) > 0 {
+		n, brk := c.nextLine(s)
+		b.WriteString(c.Truncate(s[:n], w, tail))
+		b.WriteString(s[n : n+brk])
+		s = s[n+brk:]
+	}
+	return b.String()
+}
+
+// MeasureBlock returns the width of the widest line in s and the number of
+// lines.
+func MeasureBlock(s string) (width, height int) {
+	return DefaultCondition.MeasureBlock(s)
+}
+
+// TruncateLines truncates every line in s to w cells, appending tail to
+// lines that were truncated.
+func TruncateLines(s string, w int, tail string) string {
+	return DefaultCondition.TruncateLines(s, w, tail)
+}
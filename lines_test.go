@@ -0,0 +1,88 @@
+package runewidth
+
+import (
+	"testing"
+)
+
+func TestMeasureBlock(t *testing.T) {
+	tests := []struct {
+		in   string
+		nl   NewlinePolicy
+		w, h int
+	}{
+		{"", 0, 0, 0},
+		{"abc", 0, 3, 1},
+		{"abc\n", 0, 3, 1},
+		{"abc\n\n", 0, 3, 2},
+		{"a\n世界\nb", 0, 4, 3},
+		{"abc\r\nde\r\n", 0, 3, 2},
+		{"abc\r\nde\r\n", NewlineCR, 3, 2},
+		{"abc\rde\r", 0, 5, 1},
+		{"abc\rde\r", NewlineCR, 3, 2},
+		{"abc\r\rde", NewlineCR, 3, 3},
+		{"abc\u2028de\u2029f", 0, 6, 1},
+		{"abc\u2028de\u2029f", NewlineUnicode, 3, 3},
+		{"ab\u2028cde\rfg", NewlineUnicode, 5, 2},
+		{"ab\u2028cde\rfg", NewlineUnicode | NewlineCR, 3, 3},
+		{"abc\u2027de", NewlineUnicode, 6, 1},
+		{"10%\r50%\r100%", NewlineCRReset, 4, 1},
+		{"100%\r50%", NewlineCRReset, 4, 1},
+		{"abc\rde\nx", NewlineCRReset, 3, 2},
+		{"abc\rde\r", NewlineCRReset | NewlineCR, 3, 2},
+	}
+
+	for _, tt := range tests {
+		c := newCond(false)
+		c.Newlines = tt.nl
+		w, h := c.MeasureBlock(tt.in)
+		if w != tt.w || h != tt.h {
+			t.Errorf("MeasureBlock(%q) = (%d, %d), want (%d, %d)", tt.in, w, h, tt.w, tt.h)
+		}
+	}
+}
+
+func TestTruncateLines(t *testing.T) {
+	tests := []struct {
+		in   string
+		nl   NewlinePolicy
+		want string
+	}{
+		{"", 0, ""},
+		{"abcdef\nab\n", 0, "abc…\nab\n"},
+		{"abcdef\r\nab\r\n", 0, "abc…\r\nab\r\n"},
+		{"abcdef\r\nab\r\n", NewlineCR, "abc…\r\nab\r\n"},
+		{"abcdef\rabcdef", 0, "abc…"},
+		{"abcdef\rabcdef", NewlineCR, "abc…\rabc…"},
+	}
+
+	for _, tt := range tests {
+		c := newCond(false)
+		c.Newlines = tt.nl
+		if have := c.TruncateLines(tt.in, 4, "…"); have != tt.want {
+			t.Errorf("TruncateLines(%q) = %q, want %q", tt.in, have, tt.want)
+		}
+	}
+}
+
+func TestWrapNewlines(t *testing.T) {
+	tests := []struct {
+		in   string
+		nl   NewlinePolicy
+		want string
+	}{
+		{"abcdef\r\nabc", 0, "abcd\nef\r\nabc"},
+		{"abcdef\r\nabc", NewlineCR, "abcd\nef\r\nabc"},
+		{"abc\rdefg", 0, "abc\rd\nefg"},
+		{"abc\rdefg", NewlineCR, "abc\rdefg"},
+		{"abc\u2028defg", 0, "abc\u2028d\nefg"},
+		{"abc\u2028defg", NewlineUnicode, "abc\u2028defg"},
+	}
+
+	for _, tt := range tests {
+		c := newCond(false)
+		c.Newlines = tt.nl
+		if have := c.Wrap(tt.in, 4); have != tt.want {
+			t.Errorf("Wrap(%q) = %q, want %q", tt.in, have, tt.want)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package runewidth
+
+import "strings"
+
+// MarkPolicy sets which invisible marks are displayed as a visible glyph; see
+// Condition.Marks.
+type MarkPolicy uint8
+
+const (
+	// MarkBOM makes U+FEFF BYTE ORDER MARK 1 cell wide, instead of zero
+	// width.
+	MarkBOM MarkPolicy = 1 << iota
+
+	// MarkDirectional makes the U+200E LEFT-TO-RIGHT MARK and U+200F
+	// RIGHT-TO-LEFT MARK directionality marks 1 cell wide, instead of zero
+	// width.
+	MarkDirectional
+)
+
+// visibleMark reports if r is a mark that's displayed with Marks.
+func (c *Condition) visibleMark(r rune) bool {
+	switch r {
+	case 0xFEFF:
+		return c.Marks&MarkBOM != 0
+	case 0x200E, 0x200F:
+		return c.Marks&MarkDirectional != 0
+	}
+	return false
+}
+
+// TrimBOM removes a U+FEFF BYTE ORDER MARK from the start of s.
+//
+// Text read from files often starts with one, and while it's zero width by
+// default it will pad the first column with MarkBOM, or if the text is
+// written to a terminal that displays it.
+func TrimBOM(s string) string {
+	return strings.TrimPrefix(s, "\ufeff")
+}
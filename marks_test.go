@@ -0,0 +1,51 @@
+package runewidth
+
+import "testing"
+
+func TestMarks(t *testing.T) {
+	tests := []struct {
+		in    string
+		marks MarkPolicy
+		w     int
+		trunc string // Truncated to 2 cells.
+	}{
+		{"\ufeffab", 0, 2, "\ufeffab"},
+		{"\ufeffab", MarkBOM, 3, "\ufeffa"},
+		{"\ufeffab", MarkDirectional, 2, "\ufeffab"},
+		{"a\ufeffb", MarkBOM, 3, "a\ufeff"},
+		{"\u200fab", 0, 2, "\u200fab"},
+		{"\u200fab", MarkDirectional, 3, "\u200fa"},
+		{"a\u200e\u200fb", MarkDirectional, 4, "a\u200e"},
+		{"a\u200e\u200fb", MarkBOM, 2, "a\u200e\u200fb"},
+		{"\ufeff\u200eab", MarkBOM | MarkDirectional, 4, "\ufeff\u200e"},
+	}
+
+	for _, tt := range tests {
+		c := newCond(false)
+		c.Marks = tt.marks
+		if have := c.StringWidth(tt.in); have != tt.w {
+			t.Errorf("StringWidth(%q) with %d = %d, want %d", tt.in, tt.marks, have, tt.w)
+		}
+		if have := c.Truncate(tt.in, 2, ""); have != tt.trunc {
+			t.Errorf("Truncate(%q, 2) with %d = %q, want %q", tt.in, tt.marks, have, tt.trunc)
+		}
+	}
+}
+
+func TestTrimBOM(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"", ""},
+		{"abc", "abc"},
+		{"\ufeffabc", "abc"},
+		{"\ufeff\ufeffabc", "\ufeffabc"},
+		{"a\ufeffbc", "a\ufeffbc"},
+	}
+
+	for _, tt := range tests {
+		if have := TrimBOM(tt.in); have != tt.want {
+			t.Errorf("TrimBOM(%q) = %q, want %q", tt.in, have, tt.want)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package runewidth
+
+// MaxRuneWidth is the largest width that RuneWidth returns, for any Condition.
+const MaxRuneWidth = 2
+
+// MaxCellWidth returns the largest number of cells a single character can take
+// up in the string functions, or -1 if there is no limit. A character is a rune
+// together with the zero-width runes that follow it, such as combining marks,
+// and is never split by functions such as Truncate and Wrap.
+//
+// This is MaxRuneWidth, or the TabWidth if that's larger. There is no limit
+// with ZWJJoin, as the width of characters joined with ZWJ is the sum of their
+// widths. Inline images with ImageWidth are not included.
+func (c *Condition) MaxCellWidth() int {
+	if c.ZWJ == ZWJJoin {
+		return -1
+	}
+	tw := c.TabWidth
+	if tw == 0 {
+		tw = 8
+	}
+	if tw > MaxRuneWidth {
+		return tw
+	}
+	return MaxRuneWidth
+}
+
+// MaxCellWidth returns the largest number of cells a single character can take
+// up in the string functions with the settings in p; see
+// Condition.MaxCellWidth.
+func (p Profile) MaxCellWidth() int {
+	return p.Condition().MaxCellWidth()
+}
+
+// MaxCellWidth returns the largest number of cells a single character can take
+// up in the string functions, or -1 if there is no limit.
+func MaxCellWidth() int {
+	return DefaultCondition.MaxCellWidth()
+}
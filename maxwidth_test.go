@@ -0,0 +1,70 @@
+package runewidth
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestMaxRuneWidth(t *testing.T) {
+	conds := []func(c *Condition){
+		func(c *Condition) {},
+		func(c *Condition) { c.AmbiguousWidth = 2 },
+		func(c *Condition) { c.ControlWidth, c.PrivateUseWidth = 2, 2 },
+		func(c *Condition) { c.NerdFonts, c.WideSymbols = true, true },
+		func(c *Condition) { c.Compat = CompatGlibc },
+		func(c *Condition) { c.SetRangeWidth('a', 'z', 5) },
+	}
+	for i, set := range conds {
+		for _, ea := range []bool{false, true} {
+			c := newCond(ea)
+			set(c)
+			for r := rune(0); r <= 0x10FFFF; r++ {
+				if w := c.RuneWidth(r); w < 0 || w > MaxRuneWidth {
+					t.Fatalf("%d/%t: %U is %d", i, ea, r, w)
+				}
+			}
+		}
+	}
+}
+
+func TestMaxCellWidth(t *testing.T) {
+	tests := []struct {
+		tab  int
+		zwj  ZWJPolicy
+		want int
+	}{
+		{0, ZWJNone, 8},
+		{-1, ZWJNone, 2},
+		{1, ZWJEmoji, 2},
+		{4, ZWJEmoji, 4},
+		{4, ZWJJoin, -1},
+	}
+	strs := []string{"a\u0301\u0302", "\U0001F1F3\U0001F1F1", "\u2764\ufe0f", "a\u20dd",
+		"\U0001F469\u200d\U0001F469\u200d\U0001F467", "\u0915\u094d\u200d\u0937", "\t"}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d/%d", tt.tab, tt.zwj), func(t *testing.T) {
+			c := newCond(true)
+			c.TabWidth, c.ZWJ = tt.tab, tt.zwj
+			c.VariationSelectors, c.RegionalIndicators, c.WideEnclosing = true, true, true
+			max := c.MaxCellWidth()
+			if max != tt.want {
+				t.Fatalf("MaxCellWidth = %d, want %d", max, tt.want)
+			}
+			if have := c.Profile().MaxCellWidth(); have != max {
+				t.Errorf("Profile.MaxCellWidth = %d", have)
+			}
+			if max == -1 {
+				return
+			}
+			for _, s := range strs {
+				for i := 0; i < len(s); {
+					n, w := c.clusterAt(s[i:], 0)
+					if w > max {
+						t.Errorf("%q: cluster %q is %d", s, s[i:i+n], w)
+					}
+					i += n
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package runewidth
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// Metrics counts how often the various code paths are used, to verify that
+// the fast paths are used. Set the Metrics field in the Condition to enable
+// it.
+//
+// The counters are updated atomically, so a Metrics can be shared between
+// several Conditions. It implements expvar.Var, so it can be published with:
+//
+//	expvar.Publish("runewidth", c.Metrics)
+type Metrics struct {
+	Lookups uint64 // Calls to RuneWidth.
+	LUTHits uint64 // Lookups that used the LUT.
+	Slow    uint64 // Lookups that searched the tables.
+	Strings uint64 // Calls to StringWidth.
+}
+
+// Snapshot returns a copy of the current counts.
+func (m *Metrics) Snapshot() Metrics {
+	return Metrics{
+		Lookups: atomic.LoadUint64(&m.Lookups),
+		LUTHits: atomic.LoadUint64(&m.LUTHits),
+		Slow:    atomic.LoadUint64(&m.Slow),
+		Strings: atomic.LoadUint64(&m.Strings),
+	}
+}
+
+// Reset sets all counts to 0.
+func (m *Metrics) Reset() {
+	atomic.StoreUint64(&m.Lookups, 0)
+	atomic.StoreUint64(&m.LUTHits, 0)
+	atomic.StoreUint64(&m.Slow, 0)
+	atomic.StoreUint64(&m.Strings, 0)
+}
+
+// String returns the counts as a JSON object.
+func (m *Metrics) String() string {
+	s := m.Snapshot()
+	return fmt.Sprintf(`{"lookups": %d, "lut_hits": %d, "slow": %d, "strings": %d}`,
+		s.Lookups, s.LUTHits, s.Slow, s.Strings)
+}
+
+func (m *Metrics) lookup(lut, slow bool) {
+	atomic.AddUint64(&m.Lookups, 1)
+	switch {
+	case lut:
+		atomic.AddUint64(&m.LUTHits, 1)
+	case slow:
+		atomic.AddUint64(&m.Slow, 1)
+	}
+}
@@ -0,0 +1,40 @@
+package runewidth
+
+import "testing"
+
+func TestMetrics(t *testing.T) {
+	c := newCond(false)
+	c.Metrics = new(Metrics)
+
+	c.RuneWidth('a')
+	c.RuneWidth('世')
+	c.StringWidth("x")
+	want := Metrics{Lookups: 3, Slow: 1, Strings: 1}
+	if have := c.Metrics.Snapshot(); have != want {
+		t.Errorf("\nhave: %+v\nwant: %+v", have, want)
+	}
+
+	c.CreateLUT()
+	c.RuneWidth('世')
+	want = Metrics{Lookups: 4, LUTHits: 1, Slow: 1, Strings: 1}
+	if have := c.Metrics.Snapshot(); have != want {
+		t.Errorf("\nhave: %+v\nwant: %+v", have, want)
+	}
+
+	wantS := `{"lookups": 4, "lut_hits": 1, "slow": 1, "strings": 1}`
+	if have := c.Metrics.String(); have != wantS {
+		t.Errorf("\nhave: %s\nwant: %s", have, wantS)
+	}
+
+	c.Metrics.Reset()
+	if have := c.Metrics.Snapshot(); have != (Metrics{}) {
+		t.Errorf("not reset: %+v", have)
+	}
+
+	// Every rune should be looked up once, even though the rune after a
+	// character is looked up to see if it's a combining character.
+	c.StringWidth("abe\u0301c")
+	if have := c.Metrics.Snapshot().Lookups; have != 5 {
+		t.Errorf("lookups: have %d; want 5", have)
+	}
+}
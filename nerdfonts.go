@@ -0,0 +1,47 @@
+package runewidth
+
+// Icons that Nerd Fonts 3.4 adds in the Private Use Areas.
+var (
+	nerdFonts = table{
+		{0xE000, 0xE00A}, // Pomicons
+		{0xE0A0, 0xE0A3}, // Powerline
+		{0xE0B0, 0xE0C8}, // Powerline and Powerline Extra
+		{0xE0CA, 0xE0CA}, // Powerline Extra
+		{0xE0CC, 0xE0D7}, // Powerline Extra
+		{0xE200, 0xE2A9}, // Font Awesome Extension
+		{0xE300, 0xE3E3}, // Weather Icons
+		{0xE5FA, 0xE6B7}, // Seti-UI and Custom
+		{0xE700, 0xE8EF}, // Devicons
+		{0xEA60, 0xEC1E}, // Codicons
+		{0xED00, 0xF2FF}, // Font Awesome
+		{0xF300, 0xF381}, // Font Logos
+		{0xF400, 0xF533}, // Octicons
+	}
+	nerdFontsWide = table{
+		{0xF0001, 0xF1AF0}, // Material Design Icons
+	}
+)
+
+// NerdFontRanges returns the ranges of the icons in the Private Use Areas that
+// are used with Condition.NerdFonts.
+func NerdFontRanges() []Range {
+	r := make([]Range, 0, len(nerdFonts)+len(nerdFontsWide))
+	for _, t := range []table{nerdFonts, nerdFontsWide} {
+		for _, iv := range t {
+			r = append(r, Range{iv.first, iv.last})
+		}
+	}
+	return r
+}
+
+// nerdFontWidth returns the width of r with NerdFonts, or -1 if it's not a
+// Nerd Fonts icon.
+func nerdFontWidth(r rune) int {
+	switch {
+	case inTable(r, nerdFonts):
+		return 1
+	case inTable(r, nerdFontsWide):
+		return 2
+	}
+	return -1
+}
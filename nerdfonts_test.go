@@ -0,0 +1,69 @@
+package runewidth
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestNerdFontsTables(t *testing.T) {
+	for _, tbl := range []table{nerdFonts, nerdFontsWide} {
+		for i, iv := range tbl {
+			if i > 0 && iv.first <= tbl[i-1].last {
+				t.Errorf("not sorted at %U", iv.first)
+			}
+			if !inTable(iv.first, private) || !inTable(iv.last, private) {
+				t.Errorf("%U..%U not in the Private Use Areas", iv.first, iv.last)
+			}
+		}
+	}
+	if have := len(NerdFontRanges()); have != len(nerdFonts)+len(nerdFontsWide) {
+		t.Errorf("NerdFontRanges() has %d ranges", have)
+	}
+}
+
+func TestNerdFonts(t *testing.T) {
+	tests := []struct {
+		in     rune
+		pw     int
+		want   int
+		wantEA int
+	}{
+		{0xE0B0, 0, 1, 1}, // Powerline
+		{0xE0B0, 2, 1, 1},
+		{0xF0001, 0, 2, 2}, // Material Design
+		{0xF0001, 1, 2, 2},
+		{0xE0C9, 0, 1, 2}, // Not used.
+		{0xE0C9, 2, 2, 2},
+		{0xF1AF1, 0, 1, 2},
+		{'a', 0, 1, 1},
+	}
+
+	for _, tt := range tests {
+		for _, ea := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%U/%d/%t", tt.in, tt.pw, ea), func(t *testing.T) {
+				c := newCond(ea)
+				c.NerdFonts, c.PrivateUseWidth = true, tt.pw
+				want := tt.want
+				if ea {
+					want = tt.wantEA
+				}
+				if have := c.RuneWidth(tt.in); have != want {
+					t.Errorf("RuneWidth = %d, want %d", have, want)
+				}
+				if have := c.Explain(tt.in).Width; have != want {
+					t.Errorf("Explain = %d, want %d", have, want)
+				}
+				c.CreateLUT()
+				if have := c.RuneWidth(tt.in); have != want {
+					t.Errorf("LUT: RuneWidth = %d, want %d", have, want)
+				}
+			})
+		}
+	}
+
+	c := newCond(true)
+	c.NerdFonts = true
+	if have := c.StringWidth("\ue0b0 \uf418 main \U000f0001"); have != 11 {
+		t.Errorf("StringWidth = %d, want 11", have)
+	}
+}
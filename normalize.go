@@ -0,0 +1,63 @@
+package runewidth
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Normalize rewrites s to a form that should display with the same width on
+// all terminals, and returns the width of the widest line.
+//
+// It removes non-printable characters other than newlines (such as control
+// characters, zero-width spaces, and byte order marks), replaces characters
+// with a Fallback, and expands tabs to spaces up to the next tab stop.
+//
+// Other characters for which IsContested is true are removed if they're zero
+// width in c, and replaced with "?" if they're not.
+func (c *Condition) Normalize(s string) (string, int) {
+	var (
+		b        strings.Builder
+		col, max int
+	)
+	b.Grow(len(s))
+	for i := 0; i < len(s); {
+		n, _ := c.cluster(s[i:])
+		switch s[i] {
+		case '\n':
+			b.WriteByte('\n')
+			col = 0
+		case '\t':
+			t := c.tabStop('\t', col, 0)
+			b.WriteString(strings.Repeat(" ", t))
+			col += t
+		default:
+			start := b.Len()
+			for j := i; j < i+n; {
+				r, size := utf8.DecodeRuneInString(s[j:])
+				if f, ok := c.Fallback(r); ok {
+					b.WriteString(f)
+				} else if IsContested(r) {
+					if c.RuneWidth(r) > 0 {
+						b.WriteByte('?')
+					}
+				} else if !inTable(r, nonprint) {
+					b.WriteString(s[j : j+size])
+				}
+				j += size
+			}
+			col += c.StringWidth(b.String()[start:])
+		}
+		if col > max {
+			max = col
+		}
+		i += n
+	}
+	return b.String(), max
+}
+
+// Normalize rewrites s to a form that should display with the same width on
+// all terminals with the settings in p, and returns the width of the widest
+// line.
+func Normalize(s string, p Profile) (string, int) {
+	return p.Condition().Normalize(s)
+}
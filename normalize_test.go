@@ -0,0 +1,38 @@
+package runewidth
+
+import (
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		in    string
+		want  string
+		wantW int
+	}{
+		{"", "", 0},
+		{"abc", "abc", 3},
+		{"a\tb", "a       b", 9},
+		{"a\u200bb\ufeff\x07c", "abc", 3},
+		{"🇳🇱—世界\nx", "NL-世界\nx", 7},
+		{"e\u0301\n\t", "e\u0301\n        ", 8},
+		{"a\u00adb\ue000c", "ab?c", 4},
+		{"\u1100\u1161x", "\u1100?x", 4},
+		{"\u2122\u2194", "??", 2},
+	}
+
+	c := newCond(false)
+	for _, tt := range tests {
+		have, w := c.Normalize(tt.in)
+		if have != tt.want || w != tt.wantW {
+			t.Errorf("Normalize(%q)\nhave: %q %d\nwant: %q %d", tt.in, have, w, tt.want, tt.wantW)
+		}
+	}
+}
+
+func TestNormalizeProfile(t *testing.T) {
+	have, w := Normalize("—\ue000", Profile{EastAsianWidth: true})
+	if want := "--?"; have != want || w != 3 {
+		t.Errorf("\nhave: %q %d\nwant: %q %d", have, w, want, 3)
+	}
+}
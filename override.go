@@ -0,0 +1,62 @@
+package runewidth
+
+// override is a width set with SetWidth or SetRangeWidth.
+type override struct {
+	first, last rune
+	width       uint8
+}
+
+// SetWidth sets the width of r to w cells; see SetRangeWidth.
+func (c *Condition) SetWidth(r rune, w int) {
+	c.SetRangeWidth(r, r, w)
+}
+
+// SetRangeWidth sets the width of all runes from first to last (inclusive) to
+// w cells, overriding the Unicode tables and all other settings. This is useful
+// for fonts such as Nerd Fonts and Powerline, which put wide glyphs in the
+// private use area, or to match the quirks of a particular terminal.
+//
+// The width is clamped to between 0 and 2. Overrides set later take precedence
+// over earlier ones if the ranges overlap.
+//
+// The lookup table is not used after this until CreateLUT is called again,
+// which will include the overrides. Overrides are not included in a Profile.
+func (c *Condition) SetRangeWidth(first, last rune, w int) {
+	if first < 0 {
+		first = 0
+	}
+	if last > 0x10FFFF {
+		last = 0x10FFFF
+	}
+	if first > last {
+		return
+	}
+	if w < 0 {
+		w = 0
+	}
+	if w > 2 {
+		w = 2
+	}
+	// Never append to the backing array of a copy of c.
+	n := len(c.overrides)
+	c.overrides = append(c.overrides[:n:n], override{first, last, uint8(w)})
+	c.invalidate()
+}
+
+// ResetWidths removes all overrides set with SetWidth and SetRangeWidth.
+func (c *Condition) ResetWidths() {
+	if len(c.overrides) > 0 {
+		c.overrides = nil
+		c.invalidate()
+	}
+}
+
+// overrideAt returns the override for r, or nil if there isn't one.
+func (c *Condition) overrideAt(r rune) *override {
+	for i := len(c.overrides) - 1; i >= 0; i-- {
+		if o := &c.overrides[i]; r >= o.first && r <= o.last {
+			return o
+		}
+	}
+	return nil
+}
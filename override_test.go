@@ -0,0 +1,77 @@
+package runewidth
+
+import "testing"
+
+func TestSetWidth(t *testing.T) {
+	c := newCond(false)
+	c.SetWidth('a', 2)
+	c.SetRangeWidth(0xE0A0, 0xE0D4, 2) // Powerline.
+	c.SetRangeWidth(0xE0B0, 0xE0B3, 1) // Later wins.
+	c.SetWidth('世', 1)
+	c.SetWidth('\x01', 5)
+	c.SetRangeWidth(-5, 0, -1)
+	c.SetRangeWidth(0x10FFFF, 0x7FFFFFFF, 2)
+	c.SetRangeWidth('z', 'b', 2) // No-op.
+
+	tests := []struct {
+		in   rune
+		want int
+	}{
+		{'a', 2},
+		{'b', 1},
+		{'z', 1},
+		{'\x00', 0},
+		{'\x01', 2},
+		{0xE0A0, 2},
+		{0xE0B0, 1},
+		{0xE0B4, 2},
+		{'世', 1},
+		{'界', 2},
+		{0x10FFFF, 2},
+		{0x110000, 0},
+	}
+
+	for _, lut := range []bool{false, true} {
+		if lut {
+			c.CreateLUT()
+		}
+		for _, tt := range tests {
+			if have := c.RuneWidth(tt.in); have != tt.want {
+				t.Errorf("lut=%t: RuneWidth(%U) = %d, want %d", lut, tt.in, have, tt.want)
+			}
+			if have := c.Explain(tt.in).Width; have != tt.want {
+				t.Errorf("lut=%t: Explain(%U) = %d, want %d", lut, tt.in, have, tt.want)
+			}
+		}
+	}
+	if have := c.Compile().StringWidth("a世"); have != 5 {
+		t.Errorf("Compiled.StringWidth = %d, want 5", have)
+	}
+
+	if have := c.Explain(0xE0A5).String(); have != "U+E0A5 '\\ue0a5' has width 2: override (U+E0A0..U+E0D4)" {
+		t.Errorf("Explain: %s", have)
+	}
+
+	g := c.Generation()
+	c.ResetWidths()
+	if c.Generation() == g {
+		t.Error("generation not changed after ResetWidths")
+	}
+	if have := c.RuneWidth('a'); have != 1 {
+		t.Errorf("after ResetWidths: RuneWidth('a') = %d, want 1", have)
+	}
+}
+
+func TestSetWidthCopy(t *testing.T) {
+	c := newCond(false)
+	c.SetWidth('a', 2)
+	c.SetWidth('b', 2)
+	c.overrides = c.overrides[:1]
+
+	cp := *c
+	cp.SetWidth('c', 2)
+	c.SetWidth('d', 2)
+	if cp.RuneWidth('d') != 1 || c.RuneWidth('c') != 1 {
+		t.Error("copies share overrides")
+	}
+}
@@ -0,0 +1,56 @@
+package runewidth
+
+// CommonPrefixWidth returns the number of cells that a and b share from the
+// start.
+//
+// Strings are compared per character (including any combining characters), so
+// "é" and "e" followed by a combining accent don't share a prefix, and a
+// prefix never ends in the middle of a wide character.
+func (c *Condition) CommonPrefixWidth(a, b string) int {
+	_, w := c.commonPrefix(a, b)
+	return w
+}
+
+// CommonPrefix returns the longest common prefix of all strings in ss and its
+// width in cells.
+//
+// This can be used in completion menus to highlight or insert the common part
+// of all candidates.
+func (c *Condition) CommonPrefix(ss ...string) (string, int) {
+	if len(ss) == 0 {
+		return "", 0
+	}
+	p := ss[0]
+	for _, s := range ss[1:] {
+		n, _ := c.commonPrefix(p, s)
+		p = p[:n]
+	}
+	return p, c.StringWidth(p)
+}
+
+// commonPrefix returns the length in bytes and the width of the common prefix
+// of a and b.
+func (c *Condition) commonPrefix(a, b string) (n, width int) {
+	for n < len(a) && n < len(b) {
+		an, aw := c.clusterAt(a[n:], width)
+		bn, _ := c.cluster(b[n:])
+		if an != bn || a[n:n+an] != b[n:n+bn] {
+			break
+		}
+		width += aw
+		n += an
+	}
+	return n, width
+}
+
+// CommonPrefixWidth returns the number of cells that a and b share from the
+// start.
+func CommonPrefixWidth(a, b string) int {
+	return DefaultCondition.CommonPrefixWidth(a, b)
+}
+
+// CommonPrefix returns the longest common prefix of all strings in ss and its
+// width in cells.
+func CommonPrefix(ss ...string) (string, int) {
+	return DefaultCondition.CommonPrefix(ss...)
+}
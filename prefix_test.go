@@ -0,0 +1,49 @@
+package runewidth
+
+import "testing"
+
+func TestCommonPrefixWidth(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 0},
+		{"abc", "abd", 2},
+		{"abc", "abc", 3},
+		{"東京都", "東京駅", 4},
+		{"東京", "東西", 2},
+		{"éx", "ex", 0},
+		{"éx", "éy", 1},
+		{"a世", "a丗", 1},
+	}
+
+	c := newCond(false)
+	for _, tt := range tests {
+		if have := c.CommonPrefixWidth(tt.a, tt.b); have != tt.want {
+			t.Errorf("CommonPrefixWidth(%q, %q) = %d, want %d", tt.a, tt.b, have, tt.want)
+		}
+	}
+}
+
+func TestCommonPrefix(t *testing.T) {
+	tests := []struct {
+		in    []string
+		want  string
+		width int
+	}{
+		{nil, "", 0},
+		{[]string{"abc"}, "abc", 3},
+		{[]string{"abc", "abd", "ab"}, "ab", 2},
+		{[]string{"東京都", "東京駅", "東京タワー"}, "東京", 4},
+		{[]string{"abc", "xyz"}, "", 0},
+	}
+
+	c := newCond(false)
+	for _, tt := range tests {
+		have, width := c.CommonPrefix(tt.in...)
+		if have != tt.want || width != tt.width {
+			t.Errorf("CommonPrefix(%q) = (%q, %d), want (%q, %d)", tt.in, have, width, tt.want, tt.width)
+		}
+	}
+}
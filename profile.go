@@ -0,0 +1,140 @@
+package runewidth
+
+import (
+	"fmt"
+	"os"
+)
+
+// Profile is a snapshot of all the settings in a Condition, which can be
+// saved (for example as JSON), logged, and later restored with Condition().
+//
+// ImageWidth, Metrics, and the overrides from SetWidth and SetRangeWidth are
+// not included.
+type Profile struct {
+	EastAsianWidth     bool          `json:"east_asian_width"`
+	StrictEmojiNeutral bool          `json:"strict_emoji_neutral"`
+	AmbiguousWidth     int           `json:"ambiguous_width"`
+	AmbiguousLocale    string        `json:"ambiguous_locale"`
+	UnicodeVersion     string        `json:"unicode_version"`
+	Compat             Compat        `json:"compat"`
+	Newlines           NewlinePolicy `json:"newlines"`
+	TabWidth           int           `json:"tab_width"`
+	ControlWidth       int           `json:"control_width"`
+	PrivateUseWidth    int           `json:"private_use_width"`
+	NerdFonts          bool          `json:"nerd_fonts"`
+	WideSymbols        bool          `json:"wide_symbols"`
+	WideEnclosing      bool          `json:"wide_enclosing"`
+	VariationSelectors bool          `json:"variation_selectors"`
+	RegionalIndicators bool          `json:"regional_indicators"`
+	ZWJ                ZWJPolicy     `json:"zwj"`
+	Marks              MarkPolicy    `json:"marks"`
+	ANSIControls       ControlPolicy `json:"ansi_controls"`
+}
+
+// Probe updates the Profile p, for example by querying the terminal.
+type Probe func(p *Profile) error
+
+// DetectProfile creates a new Profile for the current environment.
+//
+// The EastAsianWidth setting is detected from the locale first, after which
+// all the probes are run in order. Finally, the RUNEWIDTH_EASTASIAN and
+// RUNEWIDTH_AMBIGUOUS environment variables override anything set by
+// detection or the probes.
+//
+// If a probe returns an error DetectProfile stops and returns the profile as
+// it was before that probe, together with the error.
+func DetectProfile(probes ...Probe) (Profile, error) {
+	p := Profile{
+		EastAsianWidth:     IsEastAsian(),
+		StrictEmojiNeutral: StrictEmojiNeutral,
+	}
+	for _, probe := range probes {
+		pp := p
+		if err := probe(&pp); err != nil {
+			return p, err
+		}
+		p = pp
+	}
+	if ea, ok := envEastAsian(); ok {
+		p.EastAsianWidth = ea
+	}
+	if aw, ok := envAmbiguousWidth(); ok {
+		p.AmbiguousWidth = aw
+	}
+	return p, nil
+}
+
+// Condition returns a new Condition with the settings from p.
+func (p Profile) Condition() *Condition {
+	return &Condition{
+		EastAsianWidth:     p.EastAsianWidth,
+		StrictEmojiNeutral: p.StrictEmojiNeutral,
+		AmbiguousWidth:     p.AmbiguousWidth,
+		AmbiguousLocale:    p.AmbiguousLocale,
+		UnicodeVersion:     p.UnicodeVersion,
+		Compat:             p.Compat,
+		Newlines:           p.Newlines,
+		TabWidth:           p.TabWidth,
+		ControlWidth:       p.ControlWidth,
+		PrivateUseWidth:    p.PrivateUseWidth,
+		NerdFonts:          p.NerdFonts,
+		WideSymbols:        p.WideSymbols,
+		WideEnclosing:      p.WideEnclosing,
+		VariationSelectors: p.VariationSelectors,
+		RegionalIndicators: p.RegionalIndicators,
+		ZWJ:                p.ZWJ,
+		Marks:              p.Marks,
+		ANSIControls:       p.ANSIControls,
+		bufs:               newBufPool(),
+	}
+}
+
+// String returns a description of p for logging.
+func (p Profile) String() string {
+	return fmt.Sprintf("eastasian=%t strictemoji=%t ambiguouswidth=%d ambiguouslocale=%q unicodeversion=%q compat=%d newlines=%d tabwidth=%d controlwidth=%d privateusewidth=%d nerdfonts=%t widesymbols=%t wideenclosing=%t variationselectors=%t regionalindicators=%t zwj=%d marks=%d ansicontrols=%d",
+		p.EastAsianWidth, p.StrictEmojiNeutral, p.AmbiguousWidth, p.AmbiguousLocale, p.UnicodeVersion, p.Compat, p.Newlines, p.TabWidth, p.ControlWidth, p.PrivateUseWidth, p.NerdFonts, p.WideSymbols, p.WideEnclosing,
+		p.VariationSelectors, p.RegionalIndicators, p.ZWJ, p.Marks, p.ANSIControls)
+}
+
+// Profile returns a snapshot of the settings in c.
+func (c *Condition) Profile() Profile {
+	return Profile{
+		EastAsianWidth:     c.EastAsianWidth,
+		StrictEmojiNeutral: c.StrictEmojiNeutral,
+		AmbiguousWidth:     c.AmbiguousWidth,
+		AmbiguousLocale:    c.AmbiguousLocale,
+		UnicodeVersion:     c.UnicodeVersion,
+		Compat:             c.Compat,
+		Newlines:           c.Newlines,
+		TabWidth:           c.TabWidth,
+		ControlWidth:       c.ControlWidth,
+		PrivateUseWidth:    c.PrivateUseWidth,
+		NerdFonts:          c.NerdFonts,
+		WideSymbols:        c.WideSymbols,
+		WideEnclosing:      c.WideEnclosing,
+		VariationSelectors: c.VariationSelectors,
+		RegionalIndicators: c.RegionalIndicators,
+		ZWJ:                c.ZWJ,
+		Marks:              c.Marks,
+		ANSIControls:       c.ANSIControls,
+	}
+}
+
+// envEastAsian returns the value of RUNEWIDTH_EASTASIAN, and false if it's not
+// set.
+func envEastAsian() (eastAsian, ok bool) {
+	env := os.Getenv("RUNEWIDTH_EASTASIAN")
+	return env == "1", env != ""
+}
+
+// envAmbiguousWidth returns the value of RUNEWIDTH_AMBIGUOUS, and false if
+// it's not set to 1 or 2.
+func envAmbiguousWidth() (width int, ok bool) {
+	switch os.Getenv("RUNEWIDTH_AMBIGUOUS") {
+	case "1":
+		return 1, true
+	case "2":
+		return 2, true
+	}
+	return 0, false
+}
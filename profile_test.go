@@ -0,0 +1,116 @@
+package runewidth
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+)
+
+// Make sure that all fields in the Condition are in the Profile.
+func TestProfileFields(t *testing.T) {
+	ct, pt := reflect.TypeOf(Condition{}), reflect.TypeOf(Profile{})
+	for i := 0; i < ct.NumField(); i++ {
+		f := ct.Field(i)
+		if f.PkgPath != "" || f.Type.Kind() == reflect.Func || f.Type.Kind() == reflect.Ptr {
+			continue
+		}
+		pf, ok := pt.FieldByName(f.Name)
+		if !ok {
+			t.Errorf("Condition.%s not in Profile", f.Name)
+			continue
+		}
+		if pf.Type != f.Type {
+			t.Errorf("Profile.%s is %s, want %s", f.Name, pf.Type, f.Type)
+		}
+	}
+	if ct.NumField() < pt.NumField() {
+		t.Errorf("Profile has more fields than Condition")
+	}
+}
+
+func TestProfileRoundtrip(t *testing.T) {
+	c := newCond(true)
+	c.StrictEmojiNeutral = false
+	c.Newlines = NewlineCR | NewlineUnicode
+	c.TabWidth = 4
+	c.AmbiguousWidth = 1
+	c.AmbiguousLocale = "ja"
+	c.UnicodeVersion = "9.0.0"
+	c.Compat = CompatMusl
+	c.ControlWidth = 2
+	c.PrivateUseWidth = 2
+	c.NerdFonts = true
+	c.WideSymbols = true
+	c.WideEnclosing = true
+	c.VariationSelectors = true
+	c.RegionalIndicators = true
+	c.ZWJ = ZWJJoin
+	c.Marks = MarkBOM | MarkDirectional
+	c.ANSIControls = ControlIgnore
+
+	j, err := json.Marshal(c.Profile())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var p Profile
+	if err := json.Unmarshal(j, &p); err != nil {
+		t.Fatal(err)
+	}
+
+	have := p.Condition()
+	if have.bufs == nil || have.bufs == c.bufs {
+		t.Error("Condition doesn't have its own buffer pool")
+	}
+	have.bufs = c.bufs
+	if !reflect.DeepEqual(have, c) {
+		t.Errorf("\nhave: %#v\nwant: %#v", have, c)
+	}
+
+	want := "eastasian=true strictemoji=false ambiguouswidth=1 ambiguouslocale=\"ja\" unicodeversion=\"9.0.0\" compat=2 newlines=3 tabwidth=4 controlwidth=2 privateusewidth=2 nerdfonts=true widesymbols=true wideenclosing=true variationselectors=true regionalindicators=true zwj=2 marks=3 ansicontrols=1"
+	if have := p.String(); have != want {
+		t.Errorf("\nhave: %s\nwant: %s", have, want)
+	}
+}
+
+func TestDetectProfile(t *testing.T) {
+	old, oldAmb := os.Getenv("RUNEWIDTH_EASTASIAN"), os.Getenv("RUNEWIDTH_AMBIGUOUS")
+	defer func() {
+		os.Setenv("RUNEWIDTH_EASTASIAN", old)
+		os.Setenv("RUNEWIDTH_AMBIGUOUS", oldAmb)
+	}()
+
+	wide := func(p *Profile) error { p.WideEnclosing = true; return nil }
+	cr := func(p *Profile) error { p.Newlines |= NewlineCR; return nil }
+	fail := func(p *Profile) error { p.EastAsianWidth = true; return errors.New("oops") }
+
+	os.Setenv("RUNEWIDTH_EASTASIAN", "0")
+	p, err := DetectProfile(wide, fail, cr)
+	if err == nil || err.Error() != "oops" {
+		t.Errorf("wrong error: %v", err)
+	}
+	if !p.WideEnclosing || p.EastAsianWidth || p.Newlines != 0 {
+		t.Errorf("wrong profile: %s", p)
+	}
+
+	os.Setenv("RUNEWIDTH_EASTASIAN", "1")
+	p, err = DetectProfile(wide, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !p.WideEnclosing || !p.EastAsianWidth || p.Newlines != NewlineCR {
+		t.Errorf("wrong profile: %s", p)
+	}
+
+	for _, tt := range []struct {
+		env  string
+		want int
+	}{{"", 0}, {"1", 1}, {"2", 2}, {"3", 0}} {
+		os.Setenv("RUNEWIDTH_AMBIGUOUS", tt.env)
+		p, _ = DetectProfile()
+		if p.AmbiguousWidth != tt.want {
+			t.Errorf("RUNEWIDTH_AMBIGUOUS=%q: AmbiguousWidth %d", tt.env, p.AmbiguousWidth)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package runewidth
+
+import "unicode"
+
+// TableToRangeTable returns the table name as a unicode.RangeTable, for use
+// with unicode.Is and other functions that accept a RangeTable. The names are
+// the same as in WriteTables: ambiguous, combining, contested, doublewidth,
+// emoji, narrow, neutral, and nonprint.
+func TableToRangeTable(name string) (*unicode.RangeTable, bool) {
+	t, ok := allTables[name]
+	if !ok {
+		return nil, false
+	}
+	return t.rangeTable(), true
+}
+
+// rangeTable converts t to a unicode.RangeTable.
+func (t table) rangeTable() *unicode.RangeTable {
+	rt := new(unicode.RangeTable)
+	for _, iv := range t {
+		first := iv.first
+		if first <= 0xFFFF {
+			last := iv.last
+			if last > 0xFFFF {
+				last = 0xFFFF
+			}
+			rt.R16 = append(rt.R16, unicode.Range16{Lo: uint16(first), Hi: uint16(last), Stride: 1})
+			if last <= unicode.MaxLatin1 {
+				rt.LatinOffset++
+			}
+			first = 0x10000
+		}
+		if iv.last >= first {
+			rt.R32 = append(rt.R32, unicode.Range32{Lo: uint32(first), Hi: uint32(iv.last), Stride: 1})
+		}
+	}
+	return rt
+}
+
+// SetRangeTableWidth sets the width of all runes in t to w cells, as with
+// SetRangeWidth. This can be used with the tables from the unicode package or
+// golang.org/x/text:
+//
+//	c.SetRangeTableWidth(unicode.Yi, 2)
+//
+// Every range in t is added as an override, so CreateLUT should be used for
+// tables with many ranges.
+func (c *Condition) SetRangeTableWidth(t *unicode.RangeTable, w int) {
+	set := func(lo, hi, stride rune) {
+		if stride == 1 {
+			c.SetRangeWidth(lo, hi, w)
+			return
+		}
+		for r := lo; r <= hi; r += stride {
+			c.SetWidth(r, w)
+		}
+	}
+	for _, r := range t.R16 {
+		set(rune(r.Lo), rune(r.Hi), rune(r.Stride))
+	}
+	for _, r := range t.R32 {
+		set(rune(r.Lo), rune(r.Hi), rune(r.Stride))
+	}
+}
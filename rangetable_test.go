@@ -0,0 +1,54 @@
+package runewidth
+
+import (
+	"testing"
+	"unicode"
+)
+
+func TestTableToRangeTable(t *testing.T) {
+	for name, tbl := range allTables {
+		t.Run(name, func(t *testing.T) {
+			rt, ok := TableToRangeTable(name)
+			if !ok {
+				t.Fatal("not found")
+			}
+			for r := rune(0); r <= unicode.MaxRune; r++ {
+				if have, want := unicode.Is(rt, r), inTable(r, tbl); have != want {
+					t.Fatalf("%U: unicode.Is = %t, want %t", r, have, want)
+				}
+			}
+		})
+	}
+
+	if _, ok := TableToRangeTable("nope"); ok {
+		t.Error("ok for unknown table")
+	}
+}
+
+func TestSetRangeTableWidth(t *testing.T) {
+	rt := &unicode.RangeTable{
+		R16: []unicode.Range16{{Lo: 'a', Hi: 'c', Stride: 1}, {Lo: 'x', Hi: 'z', Stride: 2}},
+		R32: []unicode.Range32{{Lo: 0x1F600, Hi: 0x1F601, Stride: 1}},
+	}
+	c := newCond(false)
+	c.SetRangeTableWidth(rt, 2)
+	c.SetRangeTableWidth(rt, 0)
+	c.SetRangeTableWidth(rt, 2)
+
+	tests := []struct {
+		in   rune
+		want int
+	}{
+		{'a', 2}, {'c', 2}, {'d', 1},
+		{'x', 2}, {'y', 1}, {'z', 2},
+		{0x1F600, 2}, {0x1F602, 2},
+	}
+	for _, tt := range tests {
+		if have := c.RuneWidth(tt.in); have != tt.want {
+			t.Errorf("%U: %d, want %d", tt.in, have, tt.want)
+		}
+	}
+	if have := c.StringWidth("abcdxyz"); have != 12 {
+		t.Errorf("StringWidth = %d, want 12", have)
+	}
+}
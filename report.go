@@ -0,0 +1,174 @@
+package runewidth
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// reportField is a struct field that's a column in Report.
+type reportField struct {
+	index         int
+	header        string
+	max, min, pri int
+	right         bool
+}
+
+// Report renders rows, which must be a slice of structs or pointers to
+// structs, as a table of the exported fields with a header, aligned in columns
+// that fit in width cells with gutter cells between them. The values are
+// formatted with fmt.Sprint, and should be on a single line. Trailing spaces
+// are removed from every line.
+//
+// The columns are laid out with FitColumns; the width is unlimited if it's
+// 0 or lower. Cells that don't fit are truncated with the tail from
+// Ellipsis().
+//
+// The columns can be configured with the "report" struct tag:
+//
+//	type Row struct {
+//		Name  string `report:"File name,min=10"`
+//		Size  int64  `report:",right,priority=1"`
+//		Owner string `report:"-"`
+//		Desc  string `report:"Description,width=40"`
+//	}
+//
+// The first value is the header, which defaults to the field name; fields with
+// a header of "-" are skipped. The options are:
+//
+//	width=n     Truncate the column to at most n cells.
+//	min=n       Minimum width, as TableColumn.Min.
+//	priority=n  Priority, as TableColumn.Priority.
+//	right       Align the cells to the right.
+//
+// Every line in the result ends with "\n".
+func (c *Condition) Report(rows interface{}, width, gutter int) (string, error) {
+	v := reflect.ValueOf(rows)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return "", fmt.Errorf("runewidth.Report: not a slice but %T", rows)
+	}
+	t := v.Type().Elem()
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return "", fmt.Errorf("runewidth.Report: not a slice of structs but %T", rows)
+	}
+	fields, err := reportFields(t)
+	if err != nil {
+		return "", err
+	}
+
+	var (
+		tail = c.Ellipsis()
+		cols = make([]TableColumn, len(fields))
+	)
+	add := func(i int, s string) {
+		if fields[i].max > 0 {
+			s = c.Truncate(s, fields[i].max, tail)
+		}
+		cols[i].Cells = append(cols[i].Cells, s)
+	}
+	for i, f := range fields {
+		cols[i].Min, cols[i].Priority = f.min, f.pri
+		add(i, f.header)
+	}
+	for i := 0; i < v.Len(); i++ {
+		row := v.Index(i)
+		if row.Kind() == reflect.Ptr {
+			if row.IsNil() {
+				for j := range fields {
+					add(j, "")
+				}
+				continue
+			}
+			row = row.Elem()
+		}
+		for j, f := range fields {
+			add(j, fmt.Sprint(row.Field(f.index).Interface()))
+		}
+	}
+
+	if width <= 0 {
+		width = int(^uint(0) >> 1)
+	}
+	var (
+		widths = c.FitColumns(cols, width, gutter)
+		gut    = strings.Repeat(" ", gutter)
+		b      strings.Builder
+		l      strings.Builder
+	)
+	for line := 0; line <= v.Len(); line++ {
+		l.Reset()
+		for i, col := range cols {
+			if widths[i] == 0 {
+				continue
+			}
+			if l.Len() > 0 {
+				l.WriteString(gut)
+			}
+			s := c.Truncate(col.Cells[line], widths[i], tail)
+			if fields[i].right {
+				l.WriteString(c.FillLeft(s, widths[i]))
+			} else {
+				l.WriteString(c.FillRight(s, widths[i]))
+			}
+		}
+		b.WriteString(strings.TrimRight(l.String(), " "))
+		b.WriteByte('\n')
+	}
+	return b.String(), nil
+}
+
+// reportFields gets the fields for Report from the struct type t.
+func reportFields(t reflect.Type) ([]reportField, error) {
+	var fields []reportField
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+		opts := strings.Split(sf.Tag.Get("report"), ",")
+		f := reportField{index: i, header: opts[0]}
+		switch f.header {
+		case "-":
+			continue
+		case "":
+			f.header = sf.Name
+		}
+		for _, o := range opts[1:] {
+			k, val := o, ""
+			if j := strings.IndexByte(o, '='); j > -1 {
+				k, val = o[:j], o[j+1:]
+			}
+			var (
+				n   int
+				err error
+			)
+			if k != "right" {
+				n, err = strconv.Atoi(val)
+			}
+			switch {
+			case k == "right" && val == "":
+				f.right = true
+			case k == "width" && err == nil:
+				f.max = n
+			case k == "min" && err == nil:
+				f.min = n
+			case k == "priority" && err == nil:
+				f.pri = n
+			default:
+				return nil, fmt.Errorf("runewidth.Report: field %s: invalid option %q", sf.Name, o)
+			}
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// Report renders rows, which must be a slice of structs, as a table aligned in
+// columns that fit in width cells.
+func Report(rows interface{}, width, gutter int) (string, error) {
+	return DefaultCondition.Report(rows, width, gutter)
+}
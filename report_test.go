@@ -0,0 +1,82 @@
+package runewidth
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReport(t *testing.T) {
+	type row struct {
+		Name    string `report:"File,min=4"`
+		Size    int    `report:",right,priority=1"`
+		Owner   string `report:"-"`
+		Desc    string `report:"Description,width=8"`
+		private int
+	}
+	rows := []*row{
+		{Name: "a.txt", Size: 12, Owner: "x", Desc: "short"},
+		{Name: "日本語.txt", Size: 1024, Desc: "a much longer description"},
+		nil,
+	}
+
+	tests := []struct {
+		width int
+		want  string
+	}{
+		{0, `
+File        Size  Descrip…
+a.txt         12  short
+日本語.txt  1024  a much …
+
+`},
+		{24, `
+File      Size  Descrip…
+a.txt       12  short
+日本語.…  1024  a much …
+
+`},
+		{14, `
+File  Size  D…
+a.t…    12  s…
+日…   1024  a…
+
+`},
+		{5, `
+Size
+  12
+1024
+
+`},
+	}
+	for _, tt := range tests {
+		have, err := newCond(false).Report(rows, tt.width, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := tt.want[1:]; have != want {
+			t.Errorf("width %d\nhave:\n%s\nwant:\n%s", tt.width, have, want)
+		}
+	}
+}
+
+func TestReportErrors(t *testing.T) {
+	tests := []struct {
+		in   interface{}
+		want string
+	}{
+		{"x", "not a slice"},
+		{[]int{1}, "not a slice of structs"},
+		{[]struct {
+			A int `report:",width=x"`
+		}{}, `invalid option "width=x"`},
+		{[]struct {
+			A int `report:",left"`
+		}{}, `invalid option "left"`},
+	}
+	for _, tt := range tests {
+		_, err := Report(tt.in, 0, 1)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%T: %v, want %q", tt.in, err, tt.want)
+		}
+	}
+}
@@ -2,7 +2,11 @@
 
 package runewidth
 
-import "os"
+import (
+	"context"
+	"os"
+	"sync"
+)
 
 var (
 	// EastAsianWidth will be set true if the current locale is CJK
@@ -23,20 +27,24 @@ func init() {
 }
 
 func handleEnv() {
-	env := os.Getenv("RUNEWIDTH_EASTASIAN")
-	if env == "" {
-		EastAsianWidth = IsEastAsian()
+	if ea, ok := envEastAsian(); ok {
+		EastAsianWidth = ea
 	} else {
-		EastAsianWidth = env == "1"
+		EastAsianWidth = IsEastAsian()
 	}
+	aw, _ := envAmbiguousWidth()
 	// update DefaultCondition
-	if DefaultCondition.EastAsianWidth != EastAsianWidth {
+	if DefaultCondition.EastAsianWidth != EastAsianWidth || DefaultCondition.AmbiguousWidth != aw {
 		DefaultCondition.EastAsianWidth = EastAsianWidth
+		DefaultCondition.AmbiguousWidth = aw
 		if len(DefaultCondition.combinedLut) > 0 {
 			DefaultCondition.combinedLut = DefaultCondition.combinedLut[:0]
 			CreateLUT()
 		}
 	}
+	if os.Getenv("RUNEWIDTH_CREATE_LUT") == "1" {
+		CreateLUT()
+	}
 }
 
 type interval struct {
@@ -92,11 +100,151 @@ var nonprint = table{
 	{0xFEFF, 0xFEFF}, {0xFFF9, 0xFFFB}, {0xFFFE, 0xFFFF},
 }
 
+// contested are runes whose displayed width is known to differ between
+// terminals, beyond the ambiguous characters: soft hyphen, Hangul Jamo
+// vowels and final consonants, the two- and three-em dash, the Arabic
+// Bismillah ligature, musical and mathematical alphanumeric symbols, regional
+// indicators, and the private use areas.
+var contested = table{
+	{0x00AD, 0x00AD}, {0x1160, 0x11FF}, {0x2E3A, 0x2E3B},
+	{0xE000, 0xF8FF}, {0xFDFD, 0xFDFD}, {0x1D100, 0x1D1FF},
+	{0x1D400, 0x1D7FF}, {0x1F1E6, 0x1F1FF}, {0xF0000, 0xFFFFD},
+	{0x100000, 0x10FFFD},
+}
+
 // Condition have flag EastAsianWidth whether the current locale is CJK or not.
+//
+// A Condition is safe for concurrent use as long as it's not modified. The
+// string functions use scratch buffers from a pool in the Condition, which is
+// safe to use from several goroutines, so there is no need to create a
+// Condition per goroutine. Conditions that aren't created with NewCondition
+// or Profile.Condition use a pool that's shared with other such Conditions.
 type Condition struct {
 	combinedLut        []byte
+	compiled           *widthTable // Set for the Condition in a Compiled.
+	lutKey             lutKey      // Settings the LUT was created with.
+	gen                uint32      // Incremented by invalidate().
+	overrides          []override  // Set with SetWidth and SetRangeWidth.
+	bufs               *sync.Pool  // Scratch buffers; see getBuf.
 	EastAsianWidth     bool
 	StrictEmojiNeutral bool
+
+	// AmbiguousWidth sets the width of characters with an ambiguous East Asian
+	// Width to 1 or 2 cells, without any of the other changes EastAsianWidth
+	// makes. Ambiguous characters are wide only with EastAsianWidth if this
+	// is any other value.
+	//
+	// The RUNEWIDTH_AMBIGUOUS environment variable sets this for
+	// DefaultCondition and DetectProfile.
+	AmbiguousWidth int
+
+	// AmbiguousLocale limits which ambiguous characters are wide to those that are double-width in the legacy
+	// encodings of a locale: "ja" (JIS X 0208), "ko" (KS X 1001), "zh-Hans"
+	// (GB 2312), or "zh-Hant" (Big5). For example, Cyrillic is wide in
+	// Japanese but not in Traditional Chinese, and "é" is wide only in
+	// Simplified Chinese (for pinyin).
+	//
+	// All ambiguous characters are wide if this is empty or an unknown
+	// locale.
+	AmbiguousLocale string
+
+	// UnicodeVersion is the version of the Unicode tables to use for wide
+	// characters, to match terminals that use the tables from an older or
+	// newer version; see UnicodeVersions() for the available versions. For
+	// example many emoji are wide since Unicode 9.0.0, and were narrow
+	// before that.
+	//
+	// The default tables are used if this is empty or an unknown version;
+	// UnicodeVersion() returns the version of those.
+	UnicodeVersion string
+
+	// Compat makes RuneWidth return the same widths as the wcwidth() from a
+	// C library, for programs that need to align their output with C
+	// programs. EastAsianWidth, StrictEmojiNeutral, AmbiguousLocale, and
+	// UnicodeVersion are ignored if this is set.
+	//
+	// RuneWidth returns 0 for characters that are non-printable in the C
+	// library; use Wcwidth to get -1 for these.
+	Compat Compat
+
+	// Newlines sets which characters the multi-line functions such as Wrap
+	// and MeasureBlock treat as line breaks; "\n" is always a line break. It
+	// also sets how StringWidth handles "\r".
+	Newlines NewlinePolicy
+
+	// TabWidth is the number of cells between tab stops; a tab advances to
+	// the next tab stop. 8 is used if this is 0, and tabs are zero-width if
+	// it's negative.
+	//
+	// This only affects the string functions; RuneWidth always returns 0 for
+	// a tab as the width depends on the column.
+	TabWidth int
+
+	// ControlWidth is the width of C0 and C1 control characters other than
+	// tab and newline: 0 to ignore them (the default), 1 for terminals and
+	// pagers that display a replacement glyph, or 2 for caret notation such
+	// as "^M" used by many text editors. Other values are treated as 0.
+	ControlWidth int
+
+	// PrivateUseWidth sets the width of characters in the Private Use Areas
+	// (U+E000 to U+F8FF, and planes 15 and 16) to 1 or 2 cells, for fonts such
+	// as Nerd Fonts that put icons there. The width from the Unicode tables is
+	// used for other values, which is 1, or 2 with EastAsianWidth.
+	PrivateUseWidth int
+
+	// NerdFonts sets the width of the icons that Nerd Fonts adds in the
+	// Private Use Areas, such as the Powerline symbols: the Material Design
+	// icons (U+F0001 to U+F1AF0) are 2 cells and all others are 1 cell. This
+	// takes precedence over PrivateUseWidth; see NerdFontRanges() for the
+	// list of icons.
+	NerdFonts bool
+
+	// WideSymbols sets the width of the Musical Symbols (U+1D100 to U+1D1FF)
+	// and Mathematical Alphanumeric Symbols (U+1D400 to U+1D7FF) to 2 cells,
+	// for fonts that draw them wide. The combining marks in these blocks
+	// remain 0 cells.
+	WideSymbols bool
+
+	// WideEnclosing makes enclosing combining marks such as U+20DD COMBINING
+	// ENCLOSING CIRCLE widen a narrow character they're attached to to 2
+	// cells, as some terminals do. This only affects the string functions.
+	WideEnclosing bool
+
+	// VariationSelectors makes U+FE0E VARIATION SELECTOR-15 (text
+	// presentation) and U+FE0F VARIATION SELECTOR-16 (emoji presentation)
+	// zero-width, and sets the width of an emoji they're attached to to 1 or
+	// 2 cells. Not all terminals do this. This only affects the string
+	// functions.
+	VariationSelectors bool
+
+	// RegionalIndicators makes a pair of regional indicator symbols (a flag
+	// such as 🇳🇱) a single character of 2 cells, rather than two characters.
+	// This only affects the string functions.
+	RegionalIndicators bool
+
+	// ZWJ sets how U+200D ZERO WIDTH JOINER joins characters. This only
+	// affects the string functions.
+	ZWJ ZWJPolicy
+
+	// Marks sets which invisible marks, such as a byte order mark, are
+	// displayed as a glyph of 1 cell, as some terminals do. This only affects
+	// the string functions. Use TrimBOM to remove a byte order mark from text
+	// read from a file.
+	Marks MarkPolicy
+
+	// ANSIControls sets how the escape-aware functions such as ClipANSI
+	// handle control characters outside of escape sequences.
+	ANSIControls ControlPolicy
+
+	// ImageWidth is called by the escape-aware functions for inline image
+	// escape sequences (kitty graphics protocol and iTerm2 inline images),
+	// and should return the number of cells the image occupies. Images are
+	// zero-width if this is nil. ImageCells can be used to get the width
+	// declared in the escape sequence.
+	ImageWidth func(seq string) int
+
+	// Metrics are updated if not nil.
+	Metrics *Metrics
 }
 
 // NewCondition return new instance of Condition which is current locale.
@@ -104,17 +252,55 @@ func NewCondition() *Condition {
 	return &Condition{
 		EastAsianWidth:     EastAsianWidth,
 		StrictEmojiNeutral: StrictEmojiNeutral,
+		bufs:               newBufPool(),
 	}
 }
 
-// RuneWidth returns the number of cells in r.
+// RuneWidth returns the number of cells in r, which is always between 0 and
+// MaxRuneWidth.
 // See http://www.unicode.org/reports/tr11/
 func (c *Condition) RuneWidth(r rune) int {
 	if r < 0 || r > 0x10FFFF {
 		return 0
 	}
+	if c.compiled != nil {
+		return c.compiled.width(r)
+	}
 	if len(c.combinedLut) > 0 {
-		return int(c.combinedLut[r>>1]>>(uint(r&1)*4)) & 3
+		if c.lutValid() {
+			if c.Metrics != nil {
+				c.Metrics.lookup(true, false)
+			}
+			return int(c.combinedLut[r>>1]>>(uint(r&1)*4)) & 3
+		}
+		c.combinedLut = nil // Settings were changed after CreateLUT.
+	}
+	if c.Metrics != nil {
+		c.Metrics.lookup(false, c.EastAsianWidth || r >= 0x300)
+	}
+	if len(c.overrides) > 0 {
+		if o := c.overrideAt(r); o != nil {
+			return int(o.width)
+		}
+	}
+	if r <= 0x9F && c.ControlWidth != 0 && isControl(r) && r != '\t' && r != '\n' {
+		return c.controlWidth()
+	}
+	if r >= 0xE000 && c.NerdFonts {
+		if nw := nerdFontWidth(r); nw > 0 {
+			return nw
+		}
+	}
+	if r >= 0xE000 && c.PrivateUseWidth != 0 && isPrivateUse(r) {
+		if pw := c.privateUseWidth(); pw > 0 {
+			return pw
+		}
+	}
+	if r >= 0x1D100 && c.WideSymbols && inTable(r, symbols) {
+		return 2
+	}
+	if c.Compat != CompatNone {
+		return c.compatWidth(r)
 	}
 	// optimized version, verified by TestRuneWidthChecksums()
 	if !c.EastAsianWidth {
@@ -123,13 +309,18 @@ func (c *Condition) RuneWidth(r rune) int {
 			return 0
 		case (r >= 0x7F && r <= 0x9F) || r == 0xAD: // nonprint
 			return 0
+		case c.AmbiguousWidth == 2 && inTable(r, ambiguous) && !inTables(r, nonprint, combining):
+			if c.ambiguousWide(r) {
+				return 2
+			}
+			return 1
 		case r < 0x300:
 			return 1
 		case inTable(r, narrow):
 			return 1
 		case inTables(r, nonprint, combining):
 			return 0
-		case inTable(r, doublewidth):
+		case inTable(r, c.doublewidth()):
 			return 2
 		default:
 			return 1
@@ -140,8 +331,13 @@ func (c *Condition) RuneWidth(r rune) int {
 			return 0
 		case inTable(r, narrow):
 			return 1
-		case inTables(r, ambiguous, doublewidth):
+		case inTable(r, c.doublewidth()):
 			return 2
+		case inTable(r, ambiguous):
+			if c.AmbiguousWidth != 1 && c.ambiguousWide(r) {
+				return 2
+			}
+			return 1
 		case !c.StrictEmojiNeutral && inTables(r, ambiguous, emoji, narrow):
 			return 2
 		default:
@@ -152,23 +348,145 @@ func (c *Condition) RuneWidth(r rune) int {
 
 // CreateLUT will create an in-memory lookup table of 557056 bytes for faster operation.
 // This should not be called concurrently with other operations on c.
-// If options in c is changed, CreateLUT should be called again.
+// If options in c is changed the LUT is no longer used, and CreateLUT should
+// be called again.
 func (c *Condition) CreateLUT() {
-	const max = 0x110000
+	_ = c.CreateLUTContext(context.Background())
+}
+
+// CreateLUTContext is like CreateLUT, but stops when ctx is cancelled and
+// returns the context's error. The Condition won't have a LUT if the
+// context was cancelled, but is otherwise still usable.
+func (c *Condition) CreateLUTContext(ctx context.Context) error {
 	lut := c.combinedLut
 	if len(c.combinedLut) != 0 {
 		// Remove so we don't use it.
 		c.combinedLut = nil
 	} else {
-		lut = make([]byte, max/2)
+		lut = make([]byte, 0x110000/2)
 	}
-	for i := range lut {
-		i32 := int32(i * 2)
-		x0 := c.RuneWidth(i32)
-		x1 := c.RuneWidth(i32 + 1)
-		lut[i] = uint8(x0) | uint8(x1)<<4
+	if err := c.fillLUT(ctx, lut); err != nil {
+		return err
+	}
+	c.combinedLut, c.lutKey = lut, c.key()
+	return nil
+}
+
+// fillLUT fills lut with the widths for all runes.
+//
+// This fills the LUT from the tables, rather than calling RuneWidth() for
+// every rune, which is a lot faster.
+func (c *Condition) fillLUT(ctx context.Context, lut []byte) error {
+	lutFill(lut, 0, 0x110000-1, 1)
+	for _, l := range c.lutLayers() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		for _, t := range l.tables {
+			for _, iv := range t {
+				lutFill(lut, iv.first, iv.last, l.width)
+			}
+		}
+	}
+	return nil
+}
+
+type lutLayer struct {
+	tables []table
+	width  uint8
+}
+
+// lutLayers returns the layers to fill the LUT with, from lowest to highest
+// priority. This must match the logic in RuneWidth().
+func (c *Condition) lutLayers() []lutLayer {
+	var l []lutLayer
+	if zero, wide, np, ok := c.compatTables(); ok {
+		l = []lutLayer{
+			{[]table{np}, 0},
+			{[]table{wide}, 2},
+			{[]table{zero}, 0},
+		}
+	} else if !c.EastAsianWidth {
+		l = []lutLayer{
+			{[]table{c.doublewidth()}, 2},
+			{[]table{nonprint, combining}, 0},
+			{[]table{narrow, {{0x0000, 0x02FF}}}, 1},
+			{[]table{{{0x0000, 0x001F}, {0x007F, 0x009F}, {0x00AD, 0x00AD}}}, 0},
+		}
+		if c.AmbiguousWidth == 2 {
+			l = append(l, lutLayer{[]table{ambiguous}, 2})
+			l = append(l, c.ambiguousLayers()...)
+			l = append(l, lutLayer{[]table{nonprint, combining, {{0x00AD, 0x00AD}}}, 0})
+		}
+	} else {
+		l = make([]lutLayer, 0, 8)
+		if !c.StrictEmojiNeutral {
+			l = append(l, lutLayer{[]table{ambiguous, emoji, narrow}, 2})
+		}
+		if c.AmbiguousWidth == 1 {
+			l = append(l, lutLayer{[]table{ambiguous}, 1}, lutLayer{[]table{c.doublewidth()}, 2})
+		} else {
+			l = append(l, lutLayer{[]table{ambiguous, c.doublewidth()}, 2})
+			l = append(l, c.ambiguousLayers()...)
+		}
+		l = append(l,
+			lutLayer{[]table{narrow}, 1},
+			lutLayer{[]table{nonprint, combining}, 0},
+		)
+	}
+	if cw := c.controlWidth(); cw > 0 {
+		l = append(l, lutLayer{[]table{{{0x0000, 0x0008}, {0x000B, 0x001F}, {0x007F, 0x009F}}}, uint8(cw)})
+	}
+	if pw := c.privateUseWidth(); pw > 0 {
+		l = append(l, lutLayer{[]table{private}, uint8(pw)})
+	}
+	if c.NerdFonts {
+		l = append(l, lutLayer{[]table{nerdFonts}, 1}, lutLayer{[]table{nerdFontsWide}, 2})
+	}
+	if c.WideSymbols {
+		l = append(l, lutLayer{[]table{symbols}, 2})
+	}
+	for _, o := range c.overrides {
+		l = append(l, lutLayer{[]table{{{o.first, o.last}}}, o.width})
+	}
+	return l
+}
+
+// controlWidth returns the width of control characters.
+func (c *Condition) controlWidth() int {
+	if c.ControlWidth == 1 || c.ControlWidth == 2 {
+		return c.ControlWidth
+	}
+	return 0
+}
+
+// privateUseWidth returns the width of private use characters, or 0 if it's
+// not set.
+func (c *Condition) privateUseWidth() int {
+	if c.PrivateUseWidth == 1 || c.PrivateUseWidth == 2 {
+		return c.PrivateUseWidth
+	}
+	return 0
+}
+
+func isPrivateUse(r rune) bool {
+	return inTable(r, private)
+}
+
+// lutFill sets the width for all runes from first to last (inclusive).
+func lutFill(lut []byte, first, last rune, w uint8) {
+	if first&1 == 1 {
+		lut[first>>1] = lut[first>>1]&0x0f | w<<4
+		first++
+	}
+	if last&1 == 0 && last >= first {
+		lut[last>>1] = lut[last>>1]&0xf0 | w
+		last--
+	}
+	b := w | w<<4
+	for i := first >> 1; i <= last>>1 && first <= last; i++ {
+		lut[i] = b
 	}
-	c.combinedLut = lut
 }
 
 // RuneWidth returns the number of cells in r.
@@ -187,10 +505,47 @@ func IsNeutralWidth(r rune) bool {
 	return inTable(r, neutral)
 }
 
+// IsCombining returns whether r is a combining mark, such as U+0301 COMBINING
+// ACUTE ACCENT. This only includes the characters with "COMBINING" in their
+// name, and not every nonspacing mark.
+func IsCombining(r rune) bool {
+	return inTable(r, combining)
+}
+
+// IsEmoji returns whether r has the Extended_Pictographic property, which
+// includes emoji and the unassigned code points reserved for them.
+func IsEmoji(r rune) bool {
+	return r == 0xA9 || r == 0xAE || inTable(r, emoji)
+}
+
+// IsNonPrint returns whether r is a non-printable character, such as a
+// control character, format character, or surrogate.
+func IsNonPrint(r rune) bool {
+	return inTable(r, nonprint)
+}
+
+// IsZeroWidth returns whether r is 0 cells wide in the Unicode tables; that
+// is, whether it's a non-printable character or combining mark.
+//
+// The width can be different with some Condition settings, such as
+// ControlWidth.
+func IsZeroWidth(r rune) bool {
+	return inTables(r, nonprint, combining)
+}
+
+// IsContested returns whether the displayed width of r is known to differ
+// between terminals, making it unsuitable for output that needs to align.
+//
+// This includes emoji that have a text presentation by default, which some
+// terminals display as two cells.
+func IsContested(r rune) bool {
+	return inTable(r, contested) || (inTable(r, emoji) && !inTable(r, doublewidth))
+}
+
 // CreateLUT will create an in-memory lookup table of 557055 bytes for faster operation.
 // This should not be called concurrently with other operations.
 func CreateLUT() {
-	if len(DefaultCondition.combinedLut) > 0 {
+	if len(DefaultCondition.combinedLut) > 0 && DefaultCondition.lutKey == DefaultCondition.key() {
 		return
 	}
 	DefaultCondition.CreateLUT()
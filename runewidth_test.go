@@ -3,6 +3,7 @@
 package runewidth
 
 import (
+	"context"
 	"crypto/sha256"
 	"fmt"
 	"os"
@@ -15,6 +16,7 @@ var _ sort.Interface = (*table)(nil) // ensure that type "table" does implement
 
 func init() {
 	os.Setenv("RUNEWIDTH_EASTASIAN", "")
+	os.Setenv("RUNEWIDTH_AMBIGUOUS", "")
 	handleEnv()
 }
 
@@ -46,6 +48,7 @@ var tables = []tableInfo{
 	{emoji, "emoji", 3535, "9ec17351601d49c535658de8d129c1d0ccda2e620669fc39a2faaee7dedcef6d"},
 	{narrow, "narrow", 111, "fa897699c5e3cd9141c638d539331b0bdd508b874e22996c5e929767d455fc5a"},
 	{neutral, "neutral", 28382, "1cbccfec7db52c7bd0e6c97c26229278a221b68afc0ca7830f1ba7e86c9b6dbc"},
+	{contested, "contested", 138938, "091beac52959eda6d68453fb8b8cab5d76c30e330da70a47e2229536d620c8a7"},
 }
 
 func TestTableChecksums(t *testing.T) {
@@ -263,6 +266,89 @@ var isambiguouswidthtests = []struct {
 	{'☆', true},
 }
 
+func TestControlWidth(t *testing.T) {
+	tests := []struct {
+		in           string
+		cw           int
+		want, wantEA int
+	}{
+		{"a\x00b\x1b\x7f\u0085", 0, 2, 2},
+		{"a\x00b\x1b\x7f\u0085", 1, 6, 6},
+		{"a\x00b\x1b\x7f\u0085", 2, 10, 10},
+		{"a\x00b", 3, 2, 2},
+		{"a\tb\nc\rd", 2, 6, 6},
+		{"a\u00adb", 2, 2, 2},
+	}
+
+	for _, tt := range tests {
+		for _, ea := range []bool{false, true} {
+			c := newCond(ea)
+			c.ControlWidth = tt.cw
+			c.TabWidth = -1
+			want := tt.want
+			if ea {
+				want = tt.wantEA
+			}
+			if have := c.StringWidth(tt.in); have != want {
+				t.Errorf("StringWidth(%q) with ControlWidth=%d, EastAsianWidth=%t = %d, want %d",
+					tt.in, tt.cw, ea, have, want)
+			}
+		}
+	}
+
+	c := newCond(false)
+	c.ControlWidth = 2
+	if have := c.Truncate("ab\x01cd", 4, ""); have != "ab\x01" {
+		t.Errorf("Truncate = %q", have)
+	}
+}
+
+func TestPrivateUseWidth(t *testing.T) {
+	tests := []struct {
+		in           rune
+		pw           int
+		want, wantEA int
+	}{
+		{0xE0B0, 0, 1, 2},
+		{0xE0B0, 1, 1, 1},
+		{0xE0B0, 2, 2, 2},
+		{0xE0B0, 3, 1, 2},
+		{0xF8FF, 2, 2, 2},
+		{0xF900, 1, 2, 2},
+		{0xF0000, 2, 2, 2},
+		{0x10FFFD, 1, 1, 1},
+		{0x10FFFE, 2, 1, 1},
+		{'a', 2, 1, 1},
+	}
+
+	for _, tt := range tests {
+		for _, ea := range []bool{false, true} {
+			c := newCond(ea)
+			c.PrivateUseWidth = tt.pw
+			want := tt.want
+			if ea {
+				want = tt.wantEA
+			}
+			if have := c.RuneWidth(tt.in); have != want {
+				t.Errorf("RuneWidth(%U) with PrivateUseWidth=%d, EastAsianWidth=%t = %d, want %d",
+					tt.in, tt.pw, ea, have, want)
+			}
+			c.CreateLUT()
+			if have := c.RuneWidth(tt.in); have != want {
+				t.Errorf("LUT: RuneWidth(%U) with PrivateUseWidth=%d, EastAsianWidth=%t = %d, want %d",
+					tt.in, tt.pw, ea, have, want)
+			}
+		}
+	}
+
+	// Also for the C library's wcwidth().
+	c := newCond(false)
+	c.Compat, c.PrivateUseWidth = CompatGlibc, 2
+	if have := c.StringWidth("\ue0b0 main"); have != 7 {
+		t.Errorf("StringWidth with Compat = %d, want 7", have)
+	}
+}
+
 func TestIsAmbiguousWidth(t *testing.T) {
 	for _, tt := range isambiguouswidthtests {
 		if out := IsAmbiguousWidth(tt.in); out != tt.out {
@@ -292,6 +378,65 @@ func TestIsNeutralWidth(t *testing.T) {
 	}
 }
 
+func TestIsZeroWidth(t *testing.T) {
+	tests := []struct {
+		in                                    rune
+		combining, emoji, nonprint, zeroWidth bool
+	}{
+		{'a', false, false, false, false},
+		{'\u0301', true, false, false, true},
+		{'\u20dd', true, false, false, true},
+		{'\x00', false, false, true, true},
+		{'\u200b', false, false, true, true},
+		{'\ufeff', false, false, true, true},
+		{'©', false, true, false, false},
+		{'❤', false, true, false, false},
+		{'🤷', false, true, false, false},
+		{0x1FAFF, false, true, false, false}, // Reserved.
+		{'世', false, false, false, false},
+	}
+	for _, tt := range tests {
+		if out := IsCombining(tt.in); out != tt.combining {
+			t.Errorf("IsCombining(%q) = %v, want %v", tt.in, out, tt.combining)
+		}
+		if out := IsEmoji(tt.in); out != tt.emoji {
+			t.Errorf("IsEmoji(%q) = %v, want %v", tt.in, out, tt.emoji)
+		}
+		if out := IsNonPrint(tt.in); out != tt.nonprint {
+			t.Errorf("IsNonPrint(%q) = %v, want %v", tt.in, out, tt.nonprint)
+		}
+		if out := IsZeroWidth(tt.in); out != tt.zeroWidth {
+			t.Errorf("IsZeroWidth(%q) = %v, want %v", tt.in, out, tt.zeroWidth)
+		}
+		if w := newCond(false).RuneWidth(tt.in); (w == 0) != tt.zeroWidth {
+			t.Errorf("RuneWidth(%q) = %d", tt.in, w)
+		}
+	}
+}
+
+func TestIsContested(t *testing.T) {
+	tests := []struct {
+		in  rune
+		out bool
+	}{
+		{'a', false},
+		{'世', false},
+		{'☆', false},
+		{'\u00ad', true},
+		{'⸺', true},
+		{'\ue0b0', true},
+		{'🇳', true},
+		{'☺', true},
+		{'❤', true},
+		{'🤷', false},
+	}
+	for _, tt := range tests {
+		if out := IsContested(tt.in); out != tt.out {
+			t.Errorf("IsContested(%q) = %v, want %v", tt.in, out, tt.out)
+		}
+	}
+}
+
 func TestEnv(t *testing.T) {
 	old := os.Getenv("RUNEWIDTH_EASTASIAN")
 	defer os.Setenv("RUNEWIDTH_EASTASIAN", old)
@@ -303,3 +448,90 @@ func TestEnv(t *testing.T) {
 		t.Errorf("RuneWidth('│') = %d, want %d", w, 1)
 	}
 }
+
+func TestEnvCreateLUT(t *testing.T) {
+	old := os.Getenv("RUNEWIDTH_CREATE_LUT")
+	defer os.Setenv("RUNEWIDTH_CREATE_LUT", old)
+	defer func() { DefaultCondition.combinedLut = nil }()
+
+	os.Setenv("RUNEWIDTH_CREATE_LUT", "")
+	handleEnv()
+	if len(DefaultCondition.combinedLut) > 0 {
+		t.Fatal("LUT created")
+	}
+
+	os.Setenv("RUNEWIDTH_CREATE_LUT", "1")
+	handleEnv()
+	if len(DefaultCondition.combinedLut) == 0 {
+		t.Fatal("LUT not created")
+	}
+	if w := RuneWidth('世'); w != 2 {
+		t.Errorf("RuneWidth('世') = %d, want %d", w, 2)
+	}
+}
+
+func TestCreateLUT(t *testing.T) {
+	for _, ea := range []bool{false, true} {
+		for _, strict := range []bool{false, true} {
+			for _, cw := range []int{0, 2} {
+				c := NewCondition()
+				c.EastAsianWidth, c.StrictEmojiNeutral, c.ControlWidth = ea, strict, cw
+				lut := NewCondition()
+				lut.EastAsianWidth, lut.StrictEmojiNeutral, lut.ControlWidth = ea, strict, cw
+				lut.CreateLUT()
+
+				for r := rune(-1); r <= utf8.MaxRune+1; r++ {
+					if have, want := lut.RuneWidth(r), c.RuneWidth(r); have != want {
+						t.Fatalf("%U: LUT has %d, want %d (EastAsianWidth=%t, StrictEmojiNeutral=%t, ControlWidth=%d)",
+							r, have, want, ea, strict, cw)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestCreateLUTContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	c := NewCondition()
+	if err := c.CreateLUTContext(ctx); err != context.Canceled {
+		t.Fatalf("wrong error: %v", err)
+	}
+	if len(c.combinedLut) > 0 {
+		t.Fatal("has LUT")
+	}
+	if w := c.RuneWidth('世'); w != 2 {
+		t.Errorf("RuneWidth('世') = %d, want %d", w, 2)
+	}
+
+	if err := c.CreateLUTContext(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(c.combinedLut) == 0 {
+		t.Fatal("no LUT")
+	}
+}
+
+func TestLUTFill(t *testing.T) {
+	tests := []struct {
+		first, last rune
+	}{
+		{0, 0}, {1, 1}, {0, 1}, {1, 2}, {2, 5}, {3, 6}, {4, 4}, {5, 7},
+	}
+	for _, tt := range tests {
+		lut := make([]byte, 5)
+		lutFill(lut, tt.first, tt.last, 2)
+		for r := rune(0); r < 10; r++ {
+			have := lut[r>>1] >> (uint(r&1) * 4) & 3
+			want := byte(0)
+			if r >= tt.first && r <= tt.last {
+				want = 2
+			}
+			if have != want {
+				t.Errorf("lutFill(%d, %d): rune %d is %d, want %d", tt.first, tt.last, r, have, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package runewidth
+
+// safeSymbols are symbols that are 1 cell wide in all configurations, aren't
+// emoji (which some terminals display as 2 cells), and are rendered by the
+// fonts of most terminals. Keep this sorted.
+var safeSymbols = table{
+	{0x2039, 0x203A}, // ‹ ›
+	{0x2042, 0x2043}, // ⁂ ⁃
+	{0x2217, 0x2217}, // ∗
+	{0x2219, 0x2219}, // ∙
+	{0x229A, 0x229B}, // ⊚ ⊛
+	{0x22EF, 0x22EF}, // ⋯
+	{0x2301, 0x2302}, // ⌁ ⌂
+	{0x25B8, 0x25B9}, // ▸ ▹
+	{0x25C2, 0x25C3}, // ◂ ◃
+	{0x25CC, 0x25CC}, // ◌
+	{0x25D2, 0x25D3}, // ◒ ◓
+	{0x25E6, 0x25E6}, // ◦
+	{0x2713, 0x2713}, // ✓
+	{0x2717, 0x2718}, // ✗ ✘
+	{0x2726, 0x2727}, // ✦ ✧
+	{0x2731, 0x2731}, // ✱
+	{0x276E, 0x276F}, // ❮ ❯
+	{0x279C, 0x279C}, // ➜
+	{0x27A4, 0x27A4}, // ➤
+	{0x27E8, 0x27E9}, // ⟨ ⟩
+	{0x2800, 0x28FF}, // Braille patterns, often used for spinners.
+	{0x2A2F, 0x2A2F}, // ⨯
+	{0x2B1D, 0x2B1E}, // ⬝ ⬞
+}
+
+// SafeSymbols returns a list of non-ASCII symbols that are 1 cell wide in all
+// configurations and on all mainstream terminals, for use as bullets, markers,
+// and spinner frames.
+//
+// This excludes characters with an ambiguous width (such as "•" and "→"),
+// emoji, and other characters that are known to be displayed differently (see
+// IsContested).
+func SafeSymbols() []rune {
+	var rs []rune
+	for _, iv := range safeSymbols {
+		for r := iv.first; r <= iv.last; r++ {
+			rs = append(rs, r)
+		}
+	}
+	return rs
+}
+
+// IsSafeSymbol reports if r is in the list returned by SafeSymbols.
+func IsSafeSymbol(r rune) bool {
+	return inTable(r, safeSymbols)
+}
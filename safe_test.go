@@ -0,0 +1,34 @@
+package runewidth
+
+import "testing"
+
+func TestSafeSymbols(t *testing.T) {
+	ea, narrow := newCond(true), newCond(false)
+	ea.StrictEmojiNeutral = false
+
+	var prev rune
+	for _, r := range SafeSymbols() {
+		if r <= prev {
+			t.Errorf("not sorted: %U after %U", r, prev)
+		}
+		prev = r
+		if !IsSafeSymbol(r) {
+			t.Errorf("IsSafeSymbol(%U) is false", r)
+		}
+		if w := narrow.RuneWidth(r); w != 1 {
+			t.Errorf("%U %q: width %d", r, r, w)
+		}
+		if w := ea.RuneWidth(r); w != 1 {
+			t.Errorf("%U %q: width %d with EastAsianWidth", r, r, w)
+		}
+		if IsAmbiguousWidth(r) || IsContested(r) || inTable(r, emoji) {
+			t.Errorf("%U %q: ambiguous, contested, or emoji", r, r)
+		}
+	}
+
+	for _, r := range "a•→😀世" {
+		if IsSafeSymbol(r) {
+			t.Errorf("IsSafeSymbol(%q) is true", r)
+		}
+	}
+}
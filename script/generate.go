@@ -10,7 +10,6 @@ import (
 	"io"
 	"net/http"
 	"os"
-	"path"
 	"strconv"
 	"strings"
 )
@@ -23,7 +22,7 @@ func fatal(err error) {
 }
 
 func get(url string) *os.File {
-	file := ".cache/" + path.Base(url)
+	file := ".cache/" + strings.ReplaceAll(strings.TrimPrefix(url, "https://www.unicode.org/Public/"), "/", "-")
 	fp, err := os.Open(file)
 	if err == nil {
 		return fp
@@ -56,6 +55,14 @@ func main() {
 	eastasian(buf, east)
 	emoji(buf, emo)
 
+	// Wide characters for older and newer versions, for
+	// Condition.UnicodeVersion.
+	for _, v := range []string{"9.0.0", "13.0.0", "16.0.0"} {
+		fmt.Fprintln(buf)
+		wide(buf, "doublewidth"+strings.Split(v, ".")[0],
+			get("https://www.unicode.org/Public/"+v+"/ucd/EastAsianWidth.txt"))
+	}
+
 	out, err := format.Source(buf.Bytes())
 	fatal(err)
 
@@ -97,11 +104,9 @@ func shapeup(p *[]rrange) {
 	*p = arr
 }
 
-func eastasian(out io.Writer, in io.Reader) {
-	var (
-		dbl, amb, cmb, na, nu []rrange
-		scanner               = bufio.NewScanner(in)
-	)
+// eachEastAsian calls fn for every range in EastAsianWidth.txt.
+func eachEastAsian(in io.Reader, fn func(r1, r2 rune, width, line string)) {
+	scanner := bufio.NewScanner(in)
 	for scanner.Scan() {
 		line := scanner.Text()
 		if strings.HasPrefix(line, "#") || len(line) == 0 {
@@ -121,13 +126,19 @@ func eastasian(out io.Writer, in io.Reader) {
 			ri2, err = strconv.ParseInt(rs2, 16, 32)
 			fatal(err)
 		}
-		r1, r2 := rune(ri1), rune(ri2)
+		fn(rune(ri1), rune(ri2), strings.TrimSpace(strings.Fields(ss)[0]), line)
+	}
+	fatal(scanner.Err())
+}
 
+func eastasian(out io.Writer, in io.Reader) {
+	var dbl, amb, cmb, na, nu, hw, fw []rrange
+	eachEastAsian(in, func(r1, r2 rune, width, line string) {
 		if strings.Index(line, "COMBINING") != -1 {
 			cmb = append(cmb, rrange{lo: r1, hi: r2})
 		}
 
-		switch strings.TrimSpace(strings.Fields(ss)[0]) {
+		switch width {
 		case "W", "F":
 			dbl = append(dbl, rrange{lo: r1, hi: r2})
 		case "A":
@@ -137,8 +148,13 @@ func eastasian(out io.Writer, in io.Reader) {
 		case "N":
 			nu = append(nu, rrange{lo: r1, hi: r2})
 		}
-	}
-	fatal(scanner.Err())
+		switch width {
+		case "H":
+			hw = append(hw, rrange{lo: r1, hi: r2})
+		case "F":
+			fw = append(fw, rrange{lo: r1, hi: r2})
+		}
+	})
 
 	shapeup(&cmb)
 	generate(out, "combining", cmb)
@@ -159,6 +175,25 @@ func eastasian(out io.Writer, in io.Reader) {
 	shapeup(&nu)
 	generate(out, "neutral", nu)
 	fmt.Fprintln(out)
+
+	shapeup(&hw)
+	generate(out, "halfwidth", hw)
+	fmt.Fprintln(out)
+
+	shapeup(&fw)
+	generate(out, "fullwidth", fw)
+	fmt.Fprintln(out)
+}
+
+func wide(out io.Writer, v string, in io.Reader) {
+	var dbl []rrange
+	eachEastAsian(in, func(r1, r2 rune, width, line string) {
+		if width == "W" || width == "F" {
+			dbl = append(dbl, rrange{lo: r1, hi: r2})
+		}
+	})
+	shapeup(&dbl)
+	generate(out, v, dbl)
 }
 
 func emoji(out io.Writer, in io.Reader) {
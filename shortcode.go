@@ -0,0 +1,55 @@
+package runewidth
+
+import (
+	"strings"
+)
+
+// StringWidthShortcodes returns the number of cells in s after replacing all
+// ":name:" shortcodes for which there is an entry in codes.
+//
+// This is useful for measuring chat messages or the like where ":smile:" is
+// displayed as "😄".
+func (c *Condition) StringWidthShortcodes(s string, codes map[string]string) int {
+	w := 0
+	shortcodes(s, codes, func(t string) { w += c.StringWidth(t) })
+	return w
+}
+
+// ExpandShortcodes replaces all ":name:" shortcodes in s for which there is
+// an entry in codes.
+func ExpandShortcodes(s string, codes map[string]string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	shortcodes(s, codes, func(t string) { b.WriteString(t) })
+	return b.String()
+}
+
+// shortcodes calls fn for every part of s, with all shortcodes that are in
+// codes replaced.
+func shortcodes(s string, codes map[string]string, fn func(string)) {
+	for {
+		i := strings.IndexByte(s, ':')
+		if i == -1 {
+			break
+		}
+		j := strings.IndexByte(s[i+1:], ':')
+		if j == -1 {
+			break
+		}
+		if r, ok := codes[s[i+1:i+1+j]]; ok {
+			fn(s[:i])
+			fn(r)
+			s = s[i+j+2:]
+		} else {
+			fn(s[:i+1])
+			s = s[i+1:]
+		}
+	}
+	fn(s)
+}
+
+// StringWidthShortcodes returns the number of cells in s after replacing all
+// ":name:" shortcodes for which there is an entry in codes.
+func StringWidthShortcodes(s string, codes map[string]string) int {
+	return DefaultCondition.StringWidthShortcodes(s, codes)
+}
@@ -0,0 +1,37 @@
+package runewidth
+
+import (
+	"testing"
+)
+
+func TestShortcodes(t *testing.T) {
+	codes := map[string]string{
+		"smile": "😄",
+		"+1":    "👍",
+		"x":     "❌",
+	}
+	tests := []struct {
+		in    string
+		want  string
+		width int
+	}{
+		{"", "", 0},
+		{"hello", "hello", 5},
+		{":smile:", "😄", 2},
+		{"hi :smile: :+1:!", "hi 😄 👍!", 9},
+		{"a:b:smile:", "a:b😄", 5},
+		{"time 12:30 :x:", "time 12:30 ❌", 13},
+		{":unknown: :", ":unknown: :", 11},
+		{"::", "::", 2},
+	}
+
+	c := newCond(false)
+	for _, tt := range tests {
+		if have := ExpandShortcodes(tt.in, codes); have != tt.want {
+			t.Errorf("ExpandShortcodes(%q) = %q, want %q", tt.in, have, tt.want)
+		}
+		if have := c.StringWidthShortcodes(tt.in, codes); have != tt.width {
+			t.Errorf("StringWidthShortcodes(%q) = %d, want %d", tt.in, have, tt.width)
+		}
+	}
+}
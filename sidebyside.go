@@ -0,0 +1,58 @@
+package runewidth
+
+import "strings"
+
+// SideBySide joins the lines of the blocks left and right, separated by
+// gutter.
+//
+// Every line in left is padded to the width of the widest line in left, so
+// that the right block starts in the same column on every line. Lines are
+// padded with empty lines if one block has fewer lines than the other, and
+// trailing white space is not added to lines where right is empty.
+//
+// Every line in the result ends with "\n".
+func (c *Condition) SideBySide(left, right, gutter string) string {
+	var (
+		lw, _ = c.MeasureBlock(left)
+		ll    = c.blockLines(left)
+		rl    = c.blockLines(right)
+		b     strings.Builder
+	)
+	b.Grow(len(left) + len(right) + (len(ll)+len(rl))*(lw+len(gutter)))
+	for i := 0; i < len(ll) || i < len(rl); i++ {
+		var l, r string
+		if i < len(ll) {
+			l = ll[i]
+		}
+		if i < len(rl) {
+			r = rl[i]
+		}
+		if r == "" {
+			b.WriteString(l)
+		} else {
+			b.WriteString(c.FillRight(l, lw))
+			b.WriteString(gutter)
+			b.WriteString(r)
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// blockLines splits s in lines, without the line breaks. A line break at the
+// end of s doesn't start a new line.
+func (c *Condition) blockLines(s string) []string {
+	var lines []string
+	for len(s) > 0 {
+		n, brk := c.nextLine(s)
+		lines = append(lines, s[:n])
+		s = s[n+brk:]
+	}
+	return lines
+}
+
+// SideBySide joins the lines of the blocks left and right, separated by
+// gutter.
+func SideBySide(left, right, gutter string) string {
+	return DefaultCondition.SideBySide(left, right, gutter)
+}
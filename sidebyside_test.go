@@ -0,0 +1,23 @@
+package runewidth
+
+import "testing"
+
+func TestSideBySide(t *testing.T) {
+	tests := []struct {
+		left, right, want string
+	}{
+		{"", "", ""},
+		{"a", "b", "a | b\n"},
+		{"abc\nd\n", "1\n2\n", "abc | 1\nd   | 2\n"},
+		{"東京\nab", "x\ny\nz", "東京 | x\nab   | y\n     | z\n"},
+		{"a\nbb\nc", "x", "a  | x\nbb\nc\n"},
+		{"", "x", " | x\n"},
+	}
+
+	c := newCond(false)
+	for _, tt := range tests {
+		if have := c.SideBySide(tt.left, tt.right, " | "); have != tt.want {
+			t.Errorf("SideBySide(%q, %q)\nhave: %q\nwant: %q", tt.left, tt.right, have, tt.want)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package runewidth
+
+import "sort"
+
+// ByDisplayWidth sorts strings by their display width, using the
+// DefaultCondition. Use Condition.ByDisplayWidth to sort with a different
+// Condition.
+//
+// Use sort.Stable() to keep strings of the same width in their original order.
+type ByDisplayWidth []string
+
+func (s ByDisplayWidth) Len() int           { return len(s) }
+func (s ByDisplayWidth) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s ByDisplayWidth) Less(i, j int) bool { return LessWidth(s[i], s[j]) }
+
+// ByDisplayWidth returns a sort.Interface to sort s by the display width of
+// the strings.
+//
+//	sort.Stable(c.ByDisplayWidth(s))
+func (c *Condition) ByDisplayWidth(s []string) sort.Interface {
+	return byWidth{c, s}
+}
+
+type byWidth struct {
+	c *Condition
+	s []string
+}
+
+func (b byWidth) Len() int           { return len(b.s) }
+func (b byWidth) Swap(i, j int)      { b.s[i], b.s[j] = b.s[j], b.s[i] }
+func (b byWidth) Less(i, j int) bool { return b.c.LessWidth(b.s[i], b.s[j]) }
+
+// CompareWidth compares the display width of a and b, returning -1 if a is
+// narrower, 1 if a is wider, and 0 if they're the same width.
+//
+// This can be used in the less function for sort.Slice() to sort by width
+// first:
+//
+//	sort.Slice(s, func(i, j int) bool {
+//		if cmp := c.CompareWidth(s[i].Name, s[j].Name); cmp != 0 {
+//			return cmp < 0
+//		}
+//		return s[i].ID < s[j].ID
+//	})
+func (c *Condition) CompareWidth(a, b string) int {
+	wa, wb := c.StringWidth(a), c.StringWidth(b)
+	switch {
+	case wa < wb:
+		return -1
+	case wa > wb:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// LessWidth reports if a is narrower than b.
+//
+// This can be used with sort.Slice().
+func (c *Condition) LessWidth(a, b string) bool {
+	return c.StringWidth(a) < c.StringWidth(b)
+}
+
+// CompareWidth compares the display width of a and b.
+func CompareWidth(a, b string) int {
+	return DefaultCondition.CompareWidth(a, b)
+}
+
+// LessWidth reports if a is narrower than b.
+func LessWidth(a, b string) bool {
+	return DefaultCondition.LessWidth(a, b)
+}
@@ -0,0 +1,48 @@
+package runewidth
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestSortByWidth(t *testing.T) {
+	old := DefaultCondition
+	defer func() { DefaultCondition = old }()
+	DefaultCondition = newCond(false)
+
+	have := []string{"abcd", "世界", "a", "あいう", "", "xy"}
+	sort.Stable(ByDisplayWidth(have))
+	want := []string{"", "a", "xy", "abcd", "世界", "あいう"}
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("\nhave: %q\nwant: %q", have, want)
+	}
+
+	have = []string{"abc", "☆", "ab", "☆☆"}
+	sort.Stable(newCond(true).ByDisplayWidth(have))
+	want = []string{"☆", "ab", "abc", "☆☆"}
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("\nhave: %q\nwant: %q", have, want)
+	}
+}
+
+func TestCompareWidth(t *testing.T) {
+	c := newCond(false)
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"ab", "世", 0},
+		{"a", "世", -1},
+		{"世界", "abc", 1},
+	}
+	for _, tt := range tests {
+		if have := c.CompareWidth(tt.a, tt.b); have != tt.want {
+			t.Errorf("CompareWidth(%q, %q) = %d, want %d", tt.a, tt.b, have, tt.want)
+		}
+		if have := c.LessWidth(tt.a, tt.b); have != (tt.want < 0) {
+			t.Errorf("LessWidth(%q, %q) = %t", tt.a, tt.b, have)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package runewidth
+
+// WidthStats are statistics about the characters in a string; see Stats.
+type WidthStats struct {
+	Narrow    int // Characters with a width of 1.
+	Wide      int // Characters with a width of 2.
+	ZeroWidth int // Characters with a width of 0.
+	Ambiguous int // Characters with an ambiguous width.
+	Emoji     int // Emoji characters.
+	Width     int // Total width.
+}
+
+// Stats returns statistics about the characters in s.
+//
+// The Narrow, Wide, and ZeroWidth counts are based on the Condition, while
+// Ambiguous and Emoji are counted regardless of the Condition. This can be
+// used for heuristics, such as deciding to use a CJK-aware layout if a
+// document contains many ambiguous or wide characters.
+func (c *Condition) Stats(s string) WidthStats {
+	var (
+		st   WidthStats
+		line int // Width at the start of the current line.
+	)
+	iter := c.clusters()
+	for i := 0; i < len(s); {
+		if s[i] == '\n' {
+			line = st.Width
+		}
+		n, cw := iter.at(s, i, st.Width-line)
+		for _, r := range s[i : i+n] {
+			switch w := c.RuneWidth(r); w {
+			case 0:
+				st.ZeroWidth++
+			case 1:
+				st.Narrow++
+			default:
+				st.Wide++
+			}
+			if inTable(r, ambiguous) {
+				st.Ambiguous++
+			}
+			if IsEmoji(r) {
+				st.Emoji++
+			}
+		}
+		st.Width += cw
+		i += n
+	}
+	return st
+}
+
+// Stats returns statistics about the characters in s.
+func Stats(s string) WidthStats {
+	return DefaultCondition.Stats(s)
+}
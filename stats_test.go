@@ -0,0 +1,26 @@
+package runewidth
+
+import (
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	tests := []struct {
+		in   string
+		ea   bool
+		want WidthStats
+	}{
+		{"", false, WidthStats{}},
+		{"abc", false, WidthStats{Narrow: 3, Width: 3}},
+		{"©®", false, WidthStats{Narrow: 2, Ambiguous: 1, Emoji: 2, Width: 2}},
+		{"a世界☆🤷́", false, WidthStats{Narrow: 2, Wide: 3, ZeroWidth: 1, Ambiguous: 2, Emoji: 1, Width: 8}},
+		{"a世界☆🤷́", true, WidthStats{Narrow: 1, Wide: 4, ZeroWidth: 1, Ambiguous: 2, Emoji: 1, Width: 9}},
+	}
+
+	for _, tt := range tests {
+		have := newCond(tt.ea).Stats(tt.in)
+		if have != tt.want {
+			t.Errorf("Stats(%q)\nhave: %+v\nwant: %+v", tt.in, have, tt.want)
+		}
+	}
+}
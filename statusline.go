@@ -0,0 +1,73 @@
+package runewidth
+
+import "strings"
+
+// Segment is a part of a line for StatusLine.
+type Segment struct {
+	Text string
+
+	// Segments with a lower priority are truncated first, and are hidden if
+	// they don't fit in Min cells; see TableColumn.
+	Priority, Min int
+}
+
+// StatusLine composes a line of exactly w cells with a left aligned, centered,
+// and right aligned segment, such as a status bar. There are at least gutter
+// cells between the segments.
+//
+// If the segments don't fit they're truncated with tail and hidden in order of
+// priority, like FitColumns does with columns. The center segment is centered
+// on the line if there is space, and otherwise in the space between the other
+// two segments. Segments with an empty Text are ignored.
+func (c *Condition) StatusLine(left, center, right Segment, w, gutter int, tail string) string {
+	if w <= 0 {
+		return ""
+	}
+	seg := []Segment{left, center, right}
+	cols := make([]TableColumn, len(seg))
+	for i, s := range seg {
+		cols[i] = TableColumn{Cells: []string{s.Text}, Min: s.Min, Priority: s.Priority}
+	}
+	widths := c.FitColumns(cols, w, gutter)
+	lw, cw, rw := widths[0], widths[1], widths[2]
+
+	var (
+		b   strings.Builder
+		col int
+	)
+	pad := func(to int) {
+		if to > col {
+			b.WriteString(strings.Repeat(" ", to-col))
+			col = to
+		}
+	}
+	if lw > 0 {
+		b.WriteString(c.FillRight(c.Truncate(left.Text, lw, tail), lw))
+		col = lw
+	}
+	if cw > 0 {
+		start := (w - cw) / 2
+		if lw > 0 && start < lw+gutter {
+			start = lw + gutter
+		}
+		if rw > 0 && start+cw > w-rw-gutter {
+			start = w - rw - gutter - cw
+		}
+		pad(start)
+		b.WriteString(c.FillRight(c.Truncate(center.Text, cw, tail), cw))
+		col += cw
+	}
+	if rw > 0 {
+		pad(w - rw)
+		b.WriteString(c.FillLeft(c.Truncate(right.Text, rw, tail), rw))
+		col += rw
+	}
+	pad(w)
+	return b.String()
+}
+
+// StatusLine composes a line of exactly w cells with a left aligned, centered,
+// and right aligned segment.
+func StatusLine(left, center, right Segment, w, gutter int, tail string) string {
+	return DefaultCondition.StatusLine(left, center, right, w, gutter, tail)
+}
@@ -0,0 +1,40 @@
+package runewidth
+
+import "testing"
+
+func TestStatusLine(t *testing.T) {
+	var (
+		left   = Segment{Text: "main.go", Priority: 2}
+		center = Segment{Text: "編集中", Priority: 0}
+		right  = Segment{Text: "12:34", Priority: 1, Min: 5}
+	)
+	tests := []struct {
+		left, center, right Segment
+		w                   int
+		want                string
+	}{
+		{left, center, right, 30, "main.go     編集中       12:34"},
+		{left, center, right, 22, "main.go 編集中   12:34"},
+		{left, center, right, 19, "main.go 編集… 12:34"},
+		{left, center, right, 16, "main.go …  12:34"},
+		{left, center, right, 14, "main.… … 12:34"},
+		{left, center, right, 12, "mai… … 12:34"},
+		{left, center, right, 5, "main…"},
+		{left, center, right, 0, ""},
+		{left, Segment{Text: "編集中", Min: 4}, right, 14, "ma… 編…  12:34"},
+		{left, Segment{Text: "編集中", Min: 4}, right, 10, "mai… 12:34"},
+		{left, Segment{}, right, 20, "main.go        12:34"},
+		{Segment{}, center, Segment{}, 10, "  編集中  "},
+		{Segment{}, center, right, 16, "    編集中 12:34"},
+		{Segment{}, Segment{}, Segment{}, 3, "   "},
+	}
+	for _, tt := range tests {
+		have := newCond(false).StatusLine(tt.left, tt.center, tt.right, tt.w, 1, "…")
+		if have != tt.want {
+			t.Errorf("%d\nhave: %q\nwant: %q", tt.w, have, tt.want)
+		}
+		if w := StringWidth(have); w != tt.w {
+			t.Errorf("%d: width %d", tt.w, w)
+		}
+	}
+}
@@ -0,0 +1,565 @@
+package runewidth
+
+import (
+	"sort"
+	"strings"
+	"sync/atomic"
+	"unicode"
+)
+
+// StringWidth returns the number of cells in s.
+//
+// This is mostly the sum of the RuneWidth() of every rune; it doesn't know
+// about grapheme clusters, but some options in the Condition (such as
+// WideEnclosing) depend on the sequence of characters.
+func (c *Condition) StringWidth(s string) (width int) {
+	if c.Metrics != nil {
+		atomic.AddUint64(&c.Metrics.Strings, 1)
+	}
+	return c.widthAt(s, 0)
+}
+
+// widthAt returns the width of s like StringWidth, if s starts at column col.
+// This is only different from StringWidth if there are tabs in s.
+func (c *Condition) widthAt(s string, col int) (width int) {
+	var max int
+	line := -col // Width at the start of the current line; width-line is the column.
+	reset := c.Newlines&(NewlineCR|NewlineCRReset) == NewlineCRReset
+	iter := c.clusters()
+	for i := 0; i < len(s); {
+		switch {
+		case reset && s[i] == '\r':
+			if width > max {
+				max = width
+			}
+			width, line = 0, 0
+			i++
+			continue
+		case s[i] == '\n':
+			line = width
+		}
+		n, w := iter.at(s, i, width-line)
+		width += w
+		i += n
+	}
+	if max > width {
+		return max
+	}
+	return width
+}
+
+// clusterAt returns the length and width of the cluster at the start of s
+// like cluster, but expands tabs to the next tab stop after column col.
+func (c *Condition) clusterAt(s string, col int) (int, int) {
+	if s[0] == '\t' {
+		return 1, c.tabStop('\t', col, 0)
+	}
+	return c.cluster(s)
+}
+
+// tabStop returns the number of cells to the next tab stop after column col
+// if b is a tab, or w if it's not.
+func (c *Condition) tabStop(b byte, col, w int) int {
+	if b != '\t' {
+		return w
+	}
+	switch {
+	case c.TabWidth < 0:
+		return 0
+	case c.TabWidth == 0:
+		return 8 - col%8
+	}
+	return c.TabWidth - col%c.TabWidth
+}
+
+// StringWidthTrimmed returns the number of cells in s, ignoring leading and
+// trailing white space.
+func (c *Condition) StringWidthTrimmed(s string) int {
+	return c.StringWidth(strings.TrimSpace(s))
+}
+
+// IndentWidth returns the number of cells of the leading white space in s, and
+// the number of cells of the rest of s excluding trailing white space.
+func (c *Condition) IndentWidth(s string) (indent, content int) {
+	t := strings.TrimLeftFunc(s, unicode.IsSpace)
+	return c.StringWidth(s[:len(s)-len(t)]), c.StringWidth(strings.TrimRightFunc(t, unicode.IsSpace))
+}
+
+// Truncate s to at most w cells, appending tail if s was truncated.
+//
+// The width of tail is included in w; if the tail is wider than w then only
+// the truncated tail is returned. The result is never wider than w, and is
+// always an empty string if w <= 0.
+//
+// Every invalid UTF-8 byte has the width of U+FFFD, which is 2 cells with
+// EastAsianWidth, and is kept as-is.
+func (c *Condition) Truncate(s string, w int, tail string) string {
+	s, _ = c.TruncateHidden(s, w, tail)
+	return s
+}
+
+// TruncateEllipsis truncates s to at most w cells, appending the tail from
+// Ellipsis() if s was truncated.
+func (c *Condition) TruncateEllipsis(s string, w int) string {
+	return c.Truncate(s, w, c.Ellipsis())
+}
+
+// TruncateWithSuffix truncates s to at most w cells like Truncate, but always
+// keeps the suffix keep, removing text before it instead. The tail is inserted
+// before the suffix.
+//
+// For example, to keep the file extension or a line number:
+//
+//	TruncateWithSuffix("some/long/path/file.go:42", 15, ".go:42", "…")  // "some/lon….go:42"
+//
+// This is identical to Truncate if s doesn't end with keep, or if keep and
+// tail don't fit in w.
+func (c *Condition) TruncateWithSuffix(s string, w int, keep, tail string) string {
+	if c.StringWidth(s) <= w {
+		return s
+	}
+	kw := c.maxWidth(keep) // Placed after the truncated text, so at any column.
+	if !strings.HasSuffix(s, keep) || kw+c.maxWidth(tail) > w {
+		return c.Truncate(s, w, tail)
+	}
+	t, _, _ := c.truncate(s[:len(s)-len(keep)], w-kw, tail)
+	return t + keep
+}
+
+// TruncateLeft is like Truncate, but removes text from the start of s, keeping
+// the end. The tail is prepended if s was truncated:
+//
+//	TruncateLeft("/home/martin/code/runewidth", 15, "…") // "…code/runewidth"
+func (c *Condition) TruncateLeft(s string, w int, tail string) string {
+	if c.StringWidth(s) <= w {
+		return s
+	}
+	tw := c.StringWidth(tail)
+	if tw > w {
+		t, _, _ := c.truncate(tail, w, "")
+		return t
+	}
+	start, _ := c.suffix(s, w-tw)
+	return tail + s[start:]
+}
+
+// TruncateMiddle is like Truncate, but removes text from the middle of s,
+// keeping the start and end. The tail is inserted where text was removed:
+//
+//	TruncateMiddle("/home/martin/code/runewidth", 15, "…") // "/home/m…newidth"
+//
+// If the available width is odd the start gets the extra cell, unless that
+// would split a wide character.
+func (c *Condition) TruncateMiddle(s string, w int, tail string) string {
+	if c.StringWidth(s) <= w {
+		return s
+	}
+	tw := c.maxWidth(tail) // Placed after the start, so at any column.
+	if tw > w {
+		t, _, _ := c.truncate(tail, w, "")
+		return t
+	}
+	avail := w - tw
+	left, lw, _ := c.truncate(s, (avail+1)/2, "")
+	start, _ := c.suffix(s[len(left):], avail-lw)
+	return left + tail + s[len(left)+start:]
+}
+
+// suffix returns the byte offset of the longest suffix of s that's at most w
+// cells wide, and its width.
+func (c *Condition) suffix(s string, w int) (start, width int) {
+	// Tabs are counted as the full tab width, as the columns change when
+	// removing text.
+	type unit struct{ off, width int }
+	units := make([]unit, 0, len(s))
+	iter := c.clusters()
+	for i := 0; i < len(s); {
+		n, cw := iter.at(s, i, 0)
+		units = append(units, unit{i, cw})
+		i += n
+	}
+	start = len(s)
+	for i := len(units) - 1; i >= 0; i-- {
+		if width+units[i].width > w {
+			break
+		}
+		width += units[i].width
+		start = units[i].off
+	}
+	return start, width
+}
+
+// Ellipsis returns the tail to use for truncated text: "…" if it's displayed
+// as a single cell, or "..." if it's not. U+2026 has an ambiguous width, so
+// it's two cells wide if EastAsianWidth is set.
+func (c *Condition) Ellipsis() string {
+	if c.RuneWidth('…') == 1 {
+		return "…"
+	}
+	return "..."
+}
+
+// TruncateHidden is like Truncate, but also returns the number of cells of s
+// that were removed; this doesn't include the width of tail.
+//
+// This can be used to display an indicator such as "… (+37)".
+func (c *Condition) TruncateHidden(s string, w int, tail string) (string, int) {
+	sw := c.StringWidth(s)
+	if sw <= w {
+		return s, 0
+	}
+	t, width, _ := c.truncate(s, w, tail)
+	return t, sw - width
+}
+
+// truncate s to w cells. It returns the truncated string, the width of the
+// part of s that was kept, and the width of the result including the tail.
+//
+// The tail is truncated if it's wider than w.
+func (c *Condition) truncate(s string, w int, tail string) (string, int, int) {
+	pos, tail, width, tw := c.truncateAt(s, w, tail)
+	return s[:pos] + tail, width, width + tw
+}
+
+// truncateAt returns the position to truncate s at and the tail to append to
+// fit in w cells, and the width of s[:pos] and the tail. If the tail is wider
+// than w then pos is 0 and the truncated tail is returned.
+func (c *Condition) truncateAt(s string, w int, tail string) (pos int, t string, width, tw int) {
+	pos, t, width, tw, _ = c.truncateFit(s, w, tail, false)
+	return pos, t, width, tw
+}
+
+// truncateFit is like truncateAt, but if whole is set it continues measuring
+// after pos to see if all of s fits in w cells, in which case pos is len(s),
+// width is the width of s, and fits is set.
+func (c *Condition) truncateFit(s string, w int, tail string, whole bool) (pos int, t string, width, tw int, fits bool) {
+	tw = c.StringWidth(tail)
+	if tw > w && tail != "" {
+		if whole {
+			if sw := c.StringWidth(s); sw <= w {
+				return len(s), tail, sw, tw, true
+			}
+		}
+		t, tw, _ := c.truncate(tail, w, "")
+		return 0, t, 0, tw, false
+	}
+	var (
+		tabs   = strings.IndexByte(tail, '\t') >= 0 // Width of tail depends on the column.
+		line   int                                  // Width at the start of the current line.
+		pw, pl int                                  // Width and line at pos.
+		i      int
+	)
+	pos = -1
+	iter := c.clusters()
+	for i < len(s) {
+		if s[i] == '\n' {
+			line = width
+		}
+		n, cw := iter.at(s, i, width-line)
+		if tabs {
+			tw = c.widthAt(tail, width+cw-line)
+		}
+		if pos == -1 && width+cw+tw > w {
+			pos, pw, pl = i, width, line
+			if !whole {
+				break
+			}
+		}
+		if width+cw > w {
+			break
+		}
+		width += cw
+		i += n
+	}
+	if i == len(s) {
+		if tabs {
+			tw = c.widthAt(tail, width-line)
+		}
+		return len(s), tail, width, tw, true
+	}
+	if tabs {
+		tw = c.widthAt(tail, pw-pl)
+	}
+	return pos, tail, pw, tw, false
+}
+
+// maxWidth returns the width of s with every tab counted as the full distance
+// between tab stops, which is the widest s can be at any column.
+func (c *Condition) maxWidth(s string) (width int) {
+	iter := c.clusters()
+	for i := 0; i < len(s); {
+		n, cw := iter.at(s, i, 0)
+		width += cw
+		i += n
+	}
+	return width
+}
+
+// TrimToWidth removes leading and trailing white space from s and then
+// truncates or pads it with spaces on the right so that it's exactly w cells
+// wide.
+//
+// The tail is appended if s was truncated.
+func (c *Condition) TrimToWidth(s string, w int, tail string) string {
+	if w <= 0 {
+		return ""
+	}
+	s = strings.TrimSpace(s)
+	pos, t, sw, tw, fits := c.truncateFit(s, w, tail, true)
+	if !fits {
+		s, sw = s[:pos]+t, sw+tw
+	}
+	if n := w - sw; n > 0 {
+		return s + strings.Repeat(" ", n)
+	}
+	return s
+}
+
+// Wrap s so that every line is at most w cells wide.
+//
+// Existing newlines are preserved. Lines are broken at the cell boundary, not
+// at word boundaries.
+//
+// s is returned unchanged if w <= 0.
+func (c *Condition) Wrap(s string, w int) string {
+	buf := c.getBuf()
+	defer c.putBuf(buf)
+	*buf = c.AppendWrap(*buf, s, w)
+	return string(*buf)
+}
+
+// AppendWrap appends the wrapped form of s to dst and returns the extended
+// buffer; see Wrap.
+//
+// This allows reusing a buffer across calls.
+func (c *Condition) AppendWrap(dst []byte, s string, w int) []byte {
+	if w <= 0 {
+		return append(dst, s...)
+	}
+	var (
+		width int
+		start int
+	)
+	iter := c.clusters()
+	for i := 0; i < len(s); {
+		if n := c.lineBreak(s[i:]); n > 0 {
+			width = 0
+			i += n
+			continue
+		}
+		n, cw := iter.at(s, i, width)
+		if width+cw > w && width > 0 {
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, '\n')
+			start, width = i, 0
+			cw = c.tabStop(s[i], 0, cw)
+		}
+		width += cw
+		i += n
+	}
+	return append(dst, s[start:]...)
+}
+
+// FillLeft pads s with spaces on the left so that it's w cells wide.
+//
+// s is returned unchanged if it's already w cells or wider, which includes
+// w <= 0.
+func (c *Condition) FillLeft(s string, w int) string {
+	if n := c.padLeft(s, w); n > 0 {
+		buf := c.getBuf()
+		defer c.putBuf(buf)
+		*buf = append(appendSpaces(*buf, n), s...)
+		return string(*buf)
+	}
+	return s
+}
+
+// FillRight pads s with spaces on the right so that it's w cells wide.
+//
+// s is returned unchanged if it's already w cells or wider, which includes
+// w <= 0.
+func (c *Condition) FillRight(s string, w int) string {
+	if n := w - c.StringWidth(s); n > 0 {
+		buf := c.getBuf()
+		defer c.putBuf(buf)
+		*buf = appendSpaces(append(*buf, s...), n)
+		return string(*buf)
+	}
+	return s
+}
+
+// AppendFillLeft appends s padded with spaces on the left so that it's w cells
+// wide to dst and returns the extended buffer; see FillLeft.
+func (c *Condition) AppendFillLeft(dst []byte, s string, w int) []byte {
+	dst = appendSpaces(dst, c.padLeft(s, w))
+	return append(dst, s...)
+}
+
+// AppendFillRight appends s padded with spaces on the right so that it's w
+// cells wide to dst and returns the extended buffer; see FillRight.
+func (c *Condition) AppendFillRight(dst []byte, s string, w int) []byte {
+	dst = append(dst, s...)
+	return appendSpaces(dst, w-c.StringWidth(s))
+}
+
+// WriteWrap writes the wrapped form of s to b; see Wrap.
+func (c *Condition) WriteWrap(b *strings.Builder, s string, w int) {
+	buf := c.getBuf()
+	defer c.putBuf(buf)
+	*buf = c.AppendWrap(*buf, s, w)
+	b.Write(*buf)
+}
+
+// WriteFillLeft writes s padded with spaces on the left so that it's w cells
+// wide to b; see FillLeft.
+func (c *Condition) WriteFillLeft(b *strings.Builder, s string, w int) {
+	n := c.padLeft(s, w)
+	if n > 0 {
+		b.Grow(n + len(s))
+	}
+	writeSpaces(b, n)
+	b.WriteString(s)
+}
+
+// WriteFillRight writes s padded with spaces on the right so that it's w cells
+// wide to b; see FillRight.
+func (c *Condition) WriteFillRight(b *strings.Builder, s string, w int) {
+	n := w - c.StringWidth(s)
+	if n > 0 {
+		b.Grow(n + len(s))
+	}
+	b.WriteString(s)
+	writeSpaces(b, n)
+}
+
+// padLeft returns the number of spaces to put before s to make it w cells
+// wide.
+//
+// Tabs in s get narrower as the text before them gets wider, so the result may
+// be narrower than w if s contains tabs. It's never wider.
+func (c *Condition) padLeft(s string, w int) int {
+	sw := c.StringWidth(s)
+	if sw >= w || strings.IndexByte(s, '\t') == -1 {
+		return w - sw
+	}
+	return sort.Search(w+1, func(n int) bool { return n+c.widthAt(s, n) > w }) - 1
+}
+
+func writeSpaces(b *strings.Builder, n int) {
+	for ; n > 0; n-- {
+		b.WriteByte(' ')
+	}
+}
+
+func appendSpaces(dst []byte, n int) []byte {
+	for ; n > 0; n-- {
+		dst = append(dst, ' ')
+	}
+	return dst
+}
+
+// StringWidth returns the number of cells in s.
+func StringWidth(s string) int {
+	return DefaultCondition.StringWidth(s)
+}
+
+// StringWidthTrimmed returns the number of cells in s, ignoring leading and
+// trailing white space.
+func StringWidthTrimmed(s string) int {
+	return DefaultCondition.StringWidthTrimmed(s)
+}
+
+// IndentWidth returns the number of cells of the leading white space in s, and
+// the number of cells of the rest of s excluding trailing white space.
+func IndentWidth(s string) (indent, content int) {
+	return DefaultCondition.IndentWidth(s)
+}
+
+// Truncate s to at most w cells, appending tail if s was truncated.
+func Truncate(s string, w int, tail string) string {
+	return DefaultCondition.Truncate(s, w, tail)
+}
+
+// TruncateEllipsis truncates s to at most w cells, appending the tail from
+// Ellipsis() if s was truncated.
+func TruncateEllipsis(s string, w int) string {
+	return DefaultCondition.TruncateEllipsis(s, w)
+}
+
+// TruncateWithSuffix truncates s to at most w cells like Truncate, but always
+// keeps the suffix keep.
+func TruncateWithSuffix(s string, w int, keep, tail string) string {
+	return DefaultCondition.TruncateWithSuffix(s, w, keep, tail)
+}
+
+// TruncateLeft is like Truncate, but removes text from the start of s.
+func TruncateLeft(s string, w int, tail string) string {
+	return DefaultCondition.TruncateLeft(s, w, tail)
+}
+
+// TruncateMiddle is like Truncate, but removes text from the middle of s.
+func TruncateMiddle(s string, w int, tail string) string {
+	return DefaultCondition.TruncateMiddle(s, w, tail)
+}
+
+// TruncateHidden is like Truncate, but also returns the number of cells of s
+// that were removed.
+func TruncateHidden(s string, w int, tail string) (string, int) {
+	return DefaultCondition.TruncateHidden(s, w, tail)
+}
+
+// TrimToWidth removes leading and trailing white space from s and then
+// truncates or pads it so that it's exactly w cells wide.
+func TrimToWidth(s string, w int, tail string) string {
+	return DefaultCondition.TrimToWidth(s, w, tail)
+}
+
+// Wrap s so that every line is at most w cells wide.
+func Wrap(s string, w int) string {
+	return DefaultCondition.Wrap(s, w)
+}
+
+// AppendWrap appends the wrapped form of s to dst and returns the extended
+// buffer.
+func AppendWrap(dst []byte, s string, w int) []byte {
+	return DefaultCondition.AppendWrap(dst, s, w)
+}
+
+// FillLeft pads s with spaces on the left so that it's w cells wide.
+func FillLeft(s string, w int) string {
+	return DefaultCondition.FillLeft(s, w)
+}
+
+// FillRight pads s with spaces on the right so that it's w cells wide.
+func FillRight(s string, w int) string {
+	return DefaultCondition.FillRight(s, w)
+}
+
+// AppendFillLeft appends s padded with spaces on the left so that it's w cells
+// wide to dst and returns the extended buffer.
+func AppendFillLeft(dst []byte, s string, w int) []byte {
+	return DefaultCondition.AppendFillLeft(dst, s, w)
+}
+
+// AppendFillRight appends s padded with spaces on the right so that it's w
+// cells wide to dst and returns the extended buffer.
+func AppendFillRight(dst []byte, s string, w int) []byte {
+	return DefaultCondition.AppendFillRight(dst, s, w)
+}
+
+// WriteWrap writes the wrapped form of s to b.
+func WriteWrap(b *strings.Builder, s string, w int) {
+	DefaultCondition.WriteWrap(b, s, w)
+}
+
+// WriteFillLeft writes s padded with spaces on the left so that it's w cells
+// wide to b.
+func WriteFillLeft(b *strings.Builder, s string, w int) {
+	DefaultCondition.WriteFillLeft(b, s, w)
+}
+
+// WriteFillRight writes s padded with spaces on the right so that it's w cells
+// wide to b.
+func WriteFillRight(b *strings.Builder, s string, w int) {
+	DefaultCondition.WriteFillRight(b, s, w)
+}
@@ -0,0 +1,452 @@
+package runewidth
+
+import (
+	"strings"
+	"testing"
+)
+
+func newCond(eastAsian bool) *Condition {
+	c := NewCondition()
+	c.EastAsianWidth = eastAsian
+	c.StrictEmojiNeutral = true
+	return c
+}
+
+var stringwidthtests = []struct {
+	in    string
+	out   int
+	eaout int
+}{
+	{"", 0, 0},
+	{"abc", 3, 3},
+	{"■㈱の世界①", 10, 12},
+	{"スター☆", 7, 8},
+	{"àb", 2, 2},
+	{"\x1b", 0, 0},
+}
+
+func TestStringWidth(t *testing.T) {
+	for _, tt := range stringwidthtests {
+		if out := newCond(false).StringWidth(tt.in); out != tt.out {
+			t.Errorf("StringWidth(%q) = %d, want %d", tt.in, out, tt.out)
+		}
+		if out := newCond(true).StringWidth(tt.in); out != tt.eaout {
+			t.Errorf("StringWidth(%q) = %d, want %d (EA)", tt.in, out, tt.eaout)
+		}
+	}
+}
+
+func TestTabWidth(t *testing.T) {
+	tests := []struct {
+		tabWidth int
+		in       string
+		width    int
+		trunc    string // Truncated to 6 cells.
+		wrap     string // Wrapped to 6 cells.
+	}{
+		{0, "\tx", 9, "", "\t\nx"},
+		{0, "ab\tx", 9, "ab", "ab\n\t\nx"},
+		{0, "a\nb\tc", 10, "a\nb", "a\nb\n\t\nc"},
+		{4, "\tx", 5, "\tx", "\tx"},
+		{4, "ab\tx", 5, "ab\tx", "ab\tx"},
+		{4, "abcde\tx", 9, "abcde", "abcde\n\tx"},
+		{4, "世\t界\tx", 9, "世\t界", "世\t界\n\tx"},
+		{-1, "a\tb\tc", 3, "a\tb\tc", "a\tb\tc"},
+	}
+
+	for _, tt := range tests {
+		c := newCond(false)
+		c.TabWidth = tt.tabWidth
+		if have := c.StringWidth(tt.in); have != tt.width {
+			t.Errorf("StringWidth(%q) with %d = %d, want %d", tt.in, tt.tabWidth, have, tt.width)
+		}
+		if have := c.Truncate(tt.in, 6, ""); have != tt.trunc {
+			t.Errorf("Truncate(%q) with %d = %q, want %q", tt.in, tt.tabWidth, have, tt.trunc)
+		}
+		if have := c.Wrap(tt.in, 6); have != tt.wrap {
+			t.Errorf("Wrap(%q) with %d = %q, want %q", tt.in, tt.tabWidth, have, tt.wrap)
+		}
+	}
+}
+
+// Tab stops depend on the column in the current line, and the column the text
+// ends up at.
+func TestTabColumn(t *testing.T) {
+	c := newCond(false)
+	c.TabWidth = 4
+
+	const in = "ab\nx\ty"
+	if have := c.StringWidth(in); have != 7 {
+		t.Errorf("StringWidth = %d, want 7", have)
+	}
+	if have := c.StringWidthANSI("ab\n\x1b[1mx\ty"); have != 7 {
+		t.Errorf("StringWidthANSI = %d, want 7", have)
+	}
+	if have := c.Stats(in).Width; have != 7 {
+		t.Errorf("Stats = %d, want 7", have)
+	}
+	if have := len(c.StringCells(in)); have != 7 {
+		t.Errorf("StringCells = %d, want 7", have)
+	}
+	b := Builder{Condition: c}
+	b.WriteString(in)
+	if have := b.Width(); have != 7 {
+		t.Errorf("Builder.Width = %d, want 7", have)
+	}
+
+	tests := []struct {
+		name, have, want string
+	}{
+		{"Truncate", c.Truncate(in, 6, ""), "ab\nx\t"},
+		{"TruncateANSI", c.TruncateANSI("ab\n\x1b[1mx\ty", 6, ""), "ab\n\x1b[1mx\t\x1b[0m"},
+		{"Truncate tail", c.Truncate("abcdefgh", 6, "\t"), "abc\t"},
+		{"FillLeft", c.FillLeft("a\t", 7), "  a\t"},
+		{"Clip", c.Clip("0\t00000", 1, 9), "   00000"},
+		{"Clip stop", c.Clip("0000\t00000", 0, 9), "0000\t0"},
+	}
+	for _, tt := range tests {
+		if tt.have != tt.want {
+			t.Errorf("%s\nhave: %q\nwant: %q", tt.name, tt.have, tt.want)
+		}
+	}
+}
+
+func TestStringWidthTrimmed(t *testing.T) {
+	tests := []struct {
+		in              string
+		trimmed         int
+		indent, content int
+	}{
+		{"", 0, 0, 0},
+		{"   ", 0, 3, 0},
+		{"abc", 3, 0, 3},
+		{"  a b  ", 3, 2, 3},
+		{"\u3000世界 ", 4, 2, 4},
+	}
+
+	c := newCond(false)
+	for _, tt := range tests {
+		if have := c.StringWidthTrimmed(tt.in); have != tt.trimmed {
+			t.Errorf("StringWidthTrimmed(%q) = %d, want %d", tt.in, have, tt.trimmed)
+		}
+		indent, content := c.IndentWidth(tt.in)
+		if indent != tt.indent || content != tt.content {
+			t.Errorf("IndentWidth(%q) = (%d, %d), want (%d, %d)", tt.in, indent, content, tt.indent, tt.content)
+		}
+	}
+}
+
+var truncatetests = []struct {
+	in   string
+	w    int
+	tail string
+	out  string
+}{
+	{"abcdefgh", 10, "...", "abcdefgh"},
+	{"abcdefgh", 8, "...", "abcdefgh"},
+	{"abcdefgh", 7, "...", "abcd..."},
+	{"abcdefgh", 5, "", "abcde"},
+	{"あいうえお", 5, "", "あい"},
+	{"あいうえお", 6, "…", "あい…"},
+	{"aあいうえお", 4, "", "aあ"},
+	{"àbc", 2, "", "àb"},
+	{"abcdef", 2, "...", ".."},
+	{"abcdef", 1, "あ", ""},
+	{"abcdef", -1, "...", ""},
+	{"ab\xffcd", 4, "…", "ab\xff…"},
+	{"\xff\xfe\xfd", 2, "", "\xff\xfe"},
+}
+
+func TestTruncate(t *testing.T) {
+	c := newCond(false)
+	for _, tt := range truncatetests {
+		if out := c.Truncate(tt.in, tt.w, tt.tail); out != tt.out {
+			t.Errorf("Truncate(%q, %d, %q) = %q, want %q", tt.in, tt.w, tt.tail, out, tt.out)
+		}
+	}
+
+	// Invalid UTF-8 is as wide as U+FFFD, which is ambiguous.
+	c = newCond(true)
+	if out := c.Truncate("\xff\xfe\xfd", 5, ""); out != "\xff\xfe" {
+		t.Errorf("Truncate with EastAsianWidth = %q, want %q", out, "\xff\xfe")
+	}
+}
+
+var wraptests = []struct {
+	in  string
+	w   int
+	out string
+}{
+	{"", 5, ""},
+	{"abc", 5, "abc"},
+	{"abcdefgh", 3, "abc\ndef\ngh"},
+	{"abc\ndefgh", 3, "abc\ndef\ngh"},
+	{"abc\n\ndef", 3, "abc\n\ndef"},
+	{"東京特許許可局", 6, "東京特\n許許可\n局"},
+	{"a東京", 2, "a\n東\n京"},
+	{"東京", 1, "東\n京"},
+	{"ab̀c", 2, "ab̀\nc"},
+	{"abc", 0, "abc"},
+	{"abc\ndef", -1, "abc\ndef"},
+}
+
+func TestWrap(t *testing.T) {
+	c := newCond(false)
+	for _, tt := range wraptests {
+		if out := c.Wrap(tt.in, tt.w); out != tt.out {
+			t.Errorf("Wrap(%q, %d) = %q, want %q", tt.in, tt.w, out, tt.out)
+		}
+	}
+}
+
+func TestAppendWrap(t *testing.T) {
+	c := newCond(false)
+	buf := []byte("> ")
+	buf = c.AppendWrap(buf, "abcdef", 4)
+	if string(buf) != "> abcd\nef" {
+		t.Errorf("got %q", buf)
+	}
+
+	buf = c.AppendWrap(buf[:0], "東京", 2)
+	if string(buf) != "東\n京" {
+		t.Errorf("got %q", buf)
+	}
+
+	var b strings.Builder
+	b.WriteString("> ")
+	c.WriteWrap(&b, "abcdef", 4)
+	if b.String() != "> abcd\nef" {
+		t.Errorf("got %q", b.String())
+	}
+}
+
+var filltests = []struct {
+	in    string
+	w     int
+	left  string
+	right string
+}{
+	{"", 2, "  ", "  "},
+	{"abc", 2, "abc", "abc"},
+	{"abc", 5, "  abc", "abc  "},
+	{"あい", 5, " あい", "あい "},
+	{"abc", 0, "abc", "abc"},
+	{"abc", -3, "abc", "abc"},
+}
+
+func TestFill(t *testing.T) {
+	c := newCond(false)
+	for _, tt := range filltests {
+		if out := c.FillLeft(tt.in, tt.w); out != tt.left {
+			t.Errorf("FillLeft(%q, %d) = %q, want %q", tt.in, tt.w, out, tt.left)
+		}
+		if out := c.FillRight(tt.in, tt.w); out != tt.right {
+			t.Errorf("FillRight(%q, %d) = %q, want %q", tt.in, tt.w, out, tt.right)
+		}
+		if out := string(c.AppendFillLeft([]byte("x"), tt.in, tt.w)); out != "x"+tt.left {
+			t.Errorf("AppendFillLeft(%q, %d) = %q, want %q", tt.in, tt.w, out, "x"+tt.left)
+		}
+		if out := string(c.AppendFillRight([]byte("x"), tt.in, tt.w)); out != "x"+tt.right {
+			t.Errorf("AppendFillRight(%q, %d) = %q, want %q", tt.in, tt.w, out, "x"+tt.right)
+		}
+
+		var b strings.Builder
+		b.WriteString("x")
+		if c.WriteFillLeft(&b, tt.in, tt.w); b.String() != "x"+tt.left {
+			t.Errorf("WriteFillLeft(%q, %d) = %q, want %q", tt.in, tt.w, b.String(), "x"+tt.left)
+		}
+		b.Reset()
+		b.WriteString("x")
+		if c.WriteFillRight(&b, tt.in, tt.w); b.String() != "x"+tt.right {
+			t.Errorf("WriteFillRight(%q, %d) = %q, want %q", tt.in, tt.w, b.String(), "x"+tt.right)
+		}
+	}
+}
+
+func TestTruncateHidden(t *testing.T) {
+	tests := []struct {
+		in     string
+		w      int
+		tail   string
+		out    string
+		hidden int
+	}{
+		{"abcdefgh", 8, "…", "abcdefgh", 0},
+		{"abcdefgh", 7, "…", "abcdef…", 2},
+		{"abcdefgh", 5, "", "abcde", 3},
+		{"あいうえお", 5, "", "あい", 6},
+		{"あいうえお", 6, "…", "あい…", 6},
+		{"abc", 0, "…", "", 3},
+		{"abc", -1, "…", "", 3},
+	}
+
+	c := newCond(false)
+	for _, tt := range tests {
+		out, hidden := c.TruncateHidden(tt.in, tt.w, tt.tail)
+		if out != tt.out || hidden != tt.hidden {
+			t.Errorf("TruncateHidden(%q, %d, %q) = (%q, %d), want (%q, %d)",
+				tt.in, tt.w, tt.tail, out, hidden, tt.out, tt.hidden)
+		}
+	}
+}
+
+func TestTrimToWidth(t *testing.T) {
+	tests := []struct {
+		in   string
+		w    int
+		tail string
+		out  string
+	}{
+		{"", 3, "", "   "},
+		{"  ab  ", 3, "…", "ab "},
+		{"\tabc\n", 3, "…", "abc"},
+		{" abcdef ", 4, "…", "abc…"},
+		{" あいう ", 5, "", "あい "},
+		{" あいう ", 4, "…", "あ… "},
+		{"abcd", 4, "…", "abcd"},
+		{"ab", 2, "...", "ab"},
+		{"abc", 2, "...", ".."},
+		{"a\tb", 10, "…", "a\tb "},
+		{"abcdefghijk", 10, "\t", "abcdefg\t  "},
+		{"abc", 0, "…", ""},
+		{"abc", -1, "…", ""},
+	}
+
+	c := newCond(false)
+	for _, tt := range tests {
+		if out := c.TrimToWidth(tt.in, tt.w, tt.tail); out != tt.out {
+			t.Errorf("TrimToWidth(%q, %d, %q) = %q, want %q", tt.in, tt.w, tt.tail, out, tt.out)
+		}
+	}
+}
+
+func TestTruncateEllipsis(t *testing.T) {
+	tests := []struct {
+		in  string
+		w   int
+		ea  bool
+		out string
+	}{
+		{"abcdef", 6, false, "abcdef"},
+		{"abcdef", 5, false, "abcd…"},
+		{"abcdef", 5, true, "ab..."},
+		{"あいうえ", 5, false, "あい…"},
+		{"あいうえ", 5, true, "あ..."},
+	}
+
+	for _, tt := range tests {
+		if out := newCond(tt.ea).TruncateEllipsis(tt.in, tt.w); out != tt.out {
+			t.Errorf("TruncateEllipsis(%q, %d) = %q, want %q (EastAsianWidth=%t)", tt.in, tt.w, out, tt.out, tt.ea)
+		}
+	}
+}
+
+func TestTruncateWithSuffix(t *testing.T) {
+	tests := []struct {
+		in         string
+		w          int
+		keep, tail string
+		out        string
+	}{
+		{"file.go", 10, ".go", "…", "file.go"},
+		{"some/long/path/file.go:42", 15, ".go:42", "…", "some/lon….go:42"},
+		{"some/long/path/file.go:42", 7, ".go:42", "…", "….go:42"},
+		{"some/long/path/file.go:42", 6, ".go:42", "…", "some/…"},
+		{"some/long/path/file.go", 10, ".txt", "…", "some/long…"},
+		{"長いファイル名.txt", 11, ".txt", "…", "長いフ….txt"},
+	}
+
+	c := newCond(false)
+	for _, tt := range tests {
+		if out := c.TruncateWithSuffix(tt.in, tt.w, tt.keep, tt.tail); out != tt.out {
+			t.Errorf("TruncateWithSuffix(%q, %d, %q, %q) = %q, want %q",
+				tt.in, tt.w, tt.keep, tt.tail, out, tt.out)
+		}
+	}
+}
+
+func TestWideEnclosing(t *testing.T) {
+	tests := []struct {
+		in         string
+		w, enclose int
+	}{
+		{"1⃝", 1, 2},
+		{"1⃝2", 2, 3},
+		{"⃝", 0, 0},
+		{"世⃝", 2, 2},
+		{"á⃠b", 2, 3},
+		{"a\n⃝", 1, 1},
+	}
+
+	for _, tt := range tests {
+		c := newCond(false)
+		if have := c.StringWidth(tt.in); have != tt.w {
+			t.Errorf("StringWidth(%q) = %d, want %d", tt.in, have, tt.w)
+		}
+		c.WideEnclosing = true
+		if have := c.StringWidth(tt.in); have != tt.enclose {
+			t.Errorf("StringWidth(%q) = %d, want %d (WideEnclosing)", tt.in, have, tt.enclose)
+		}
+	}
+
+	c := newCond(false)
+	c.WideEnclosing = true
+	if have := c.Truncate("1⃝2⃝3⃝", 4, ""); have != "1⃝2⃝" {
+		t.Errorf("Truncate: %q", have)
+	}
+	if have := c.Wrap("1⃝2⃝3⃝", 3); have != "1⃝\n2⃝\n3⃝" {
+		t.Errorf("Wrap: %q", have)
+	}
+}
+
+func TestAllocs(t *testing.T) {
+	c := newCond(false)
+	tests := []struct {
+		name string
+		f    func()
+	}{
+		{"Wrap", func() { c.Wrap("abcdefghijklmnopqrstuvwxyz", 5) }},
+		{"FillLeft", func() { c.FillLeft("abc", 20) }},
+		{"FillRight", func() { c.FillRight("abc", 20) }},
+	}
+	for _, tt := range tests {
+		tt.f() // Make sure the pool has a buffer.
+		if n := testing.AllocsPerRun(100, tt.f); n > 1 {
+			t.Errorf("%s: %f allocations, want at most 1", tt.name, n)
+		}
+	}
+}
+
+func TestTruncateLeftMiddle(t *testing.T) {
+	tests := []struct {
+		in           string
+		w            int
+		tail         string
+		left, middle string
+	}{
+		{"abc", 3, "…", "abc", "abc"},
+		{"/home/martin/code/runewidth", 15, "…", "…code/runewidth", "/home/m…newidth"},
+		{"abcdefgh", 5, "...", "...gh", "a...h"},
+		{"あいうえお", 6, "…", "…えお", "あ…お"},
+		{"あいうえお", 7, "…", "…うえお", "あ…えお"},
+		{"あいうえお", 5, "…", "…えお", "あ…お"},
+		{"abcdef", 2, "...", "..", ".."},
+		{"abcdef", 0, "…", "", ""},
+		{"ábcdé", 3, "", "cdé", "ábé"},
+	}
+
+	c := newCond(false)
+	for _, tt := range tests {
+		if have := c.TruncateLeft(tt.in, tt.w, tt.tail); have != tt.left {
+			t.Errorf("TruncateLeft(%q, %d, %q) = %q, want %q", tt.in, tt.w, tt.tail, have, tt.left)
+		}
+		if have := c.TruncateMiddle(tt.in, tt.w, tt.tail); have != tt.middle {
+			t.Errorf("TruncateMiddle(%q, %d, %q) = %q, want %q", tt.in, tt.w, tt.tail, have, tt.middle)
+		}
+		if tt.w >= 0 {
+			if have := c.StringWidth(c.TruncateMiddle(tt.in, tt.w, tt.tail)); have > tt.w {
+				t.Errorf("TruncateMiddle(%q, %d, %q) too wide: %d", tt.in, tt.w, tt.tail, have)
+			}
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package runewidth
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestWideSymbols(t *testing.T) {
+	tests := []struct {
+		in   rune
+		want int
+	}{
+		{0x1D11E, 2}, // MUSICAL SYMBOL G CLEF
+		{0x1D165, 0}, // MUSICAL SYMBOL COMBINING STEM
+		{0x1D173, 1}, // MUSICAL SYMBOL BEGIN BEAM
+		{0x1D400, 2}, // MATHEMATICAL BOLD CAPITAL A
+		{0x1D455, 1}, // Unassigned.
+		{0x1D7FF, 2}, // MATHEMATICAL MONOSPACE DIGIT NINE
+		{'a', 1},
+	}
+
+	for _, tt := range tests {
+		for _, ea := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%U/%t", tt.in, ea), func(t *testing.T) {
+				c := newCond(ea)
+				c.WideSymbols = true
+				if have := c.RuneWidth(tt.in); have != tt.want {
+					t.Errorf("RuneWidth = %d, want %d", have, tt.want)
+				}
+				if have := c.Explain(tt.in).Width; have != tt.want {
+					t.Errorf("Explain = %d, want %d", have, tt.want)
+				}
+				c.CreateLUT()
+				if have := c.RuneWidth(tt.in); have != tt.want {
+					t.Errorf("LUT: RuneWidth = %d, want %d", have, tt.want)
+				}
+			})
+		}
+	}
+
+	for _, r := range []rune{0x1D11E, 0x1D400} {
+		if !IsContested(r) {
+			t.Errorf("%U not contested", r)
+		}
+		if have := newCond(false).RuneWidth(r); have != 1 {
+			t.Errorf("%U: RuneWidth = %d without WideSymbols", r, have)
+		}
+	}
+}
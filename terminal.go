@@ -0,0 +1,75 @@
+package runewidth
+
+import (
+	"sort"
+	"strings"
+)
+
+// terminals are the settings for terminal emulators in their default
+// configuration.
+var terminals = map[string]Profile{
+	// Uses the latest Unicode tables, makes emoji with VS16 wide, and treats
+	// flags as a single character.
+	"kitty": {
+		StrictEmojiNeutral: true,
+		VariationSelectors: true,
+		RegionalIndicators: true,
+	},
+
+	// Uses the Unicode 9 tables by default (the unicode_version setting),
+	// and ignores VS16 unless unicode_version is 14 or newer.
+	"wezterm": {
+		StrictEmojiNeutral: true,
+		UnicodeVersion:     "9.0.0",
+		RegionalIndicators: true,
+	},
+
+	// Measures every codepoint on its own, without grapheme clustering.
+	"alacritty": {
+		StrictEmojiNeutral: true,
+	},
+
+	// Uses its own copy of wcwidth() without grapheme clustering; ambiguous
+	// characters are wide only with the cjkWidth resource.
+	"xterm": {
+		StrictEmojiNeutral: true,
+	},
+
+	// Uses wcwidth() from the C library, regardless of the outer terminal.
+	"tmux": {
+		StrictEmojiNeutral: true,
+		Compat:             CompatGlibc,
+	},
+
+	// Makes emoji with VS16 wide, but has no flag emoji on Windows.
+	"windows-terminal": {
+		StrictEmojiNeutral: true,
+		VariationSelectors: true,
+	},
+}
+
+// ConditionForTerminal returns a new Condition with the settings for the
+// terminal emulator name in its default configuration, or false if the
+// terminal isn't known; see Terminals() for the list of terminals.
+//
+// The EastAsianWidth setting is always false, as none of the terminals display
+// ambiguous characters as wide by default; set it if the terminal is
+// configured to do so.
+func ConditionForTerminal(name string) (*Condition, bool) {
+	p, ok := terminals[strings.ToLower(name)]
+	if !ok {
+		return nil, false
+	}
+	return p.Condition(), true
+}
+
+// Terminals returns the names of the terminal emulators that can be used with
+// ConditionForTerminal.
+func Terminals() []string {
+	t := make([]string, 0, len(terminals))
+	for k := range terminals {
+		t = append(t, k)
+	}
+	sort.Strings(t)
+	return t
+}
@@ -0,0 +1,47 @@
+package runewidth
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConditionForTerminal(t *testing.T) {
+	tests := []struct {
+		term  string
+		in    string
+		width int
+		trunc string // Truncated to 1 cell.
+	}{
+		{"kitty", "❤️x", 3, ""},
+		{"kitty", "🇳🇱x", 3, ""},
+		{"alacritty", "❤️x", 3, "❤"},
+		{"alacritty", "🇳🇱x", 3, "🇳"},
+		{"wezterm", "🤻", 2, ""},
+		{"Kitty", "🤻", 1, "🤻"},
+		{"windows-terminal", "❤️x", 3, ""},
+		{"windows-terminal", "🇳🇱x", 3, "🇳"},
+		{"tmux", "֑x", 1, "֑x"},
+		{"xterm", "֑x", 2, "֑"},
+	}
+
+	for _, tt := range tests {
+		c, ok := ConditionForTerminal(tt.term)
+		if !ok {
+			t.Fatalf("unknown terminal %q", tt.term)
+		}
+		if have := c.StringWidth(tt.in); have != tt.width {
+			t.Errorf("%s: StringWidth(%q) = %d, want %d", tt.term, tt.in, have, tt.width)
+		}
+		if have := c.Truncate(tt.in, 1, ""); have != tt.trunc {
+			t.Errorf("%s: Truncate(%q) = %q, want %q", tt.term, tt.in, have, tt.trunc)
+		}
+	}
+
+	if c, ok := ConditionForTerminal("nonexistent"); ok || c != nil {
+		t.Errorf("ConditionForTerminal(nonexistent) = %v, %t", c, ok)
+	}
+	want := []string{"alacritty", "kitty", "tmux", "wezterm", "windows-terminal", "xterm"}
+	if have := Terminals(); !reflect.DeepEqual(have, want) {
+		t.Errorf("Terminals() = %q", have)
+	}
+}
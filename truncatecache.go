@@ -0,0 +1,97 @@
+package runewidth
+
+import (
+	"container/list"
+	"sync"
+)
+
+// TruncateCache caches the results of Truncate, for UIs that truncate the same
+// strings over and over again, such as a table that's redrawn on every scroll.
+//
+// It holds a fixed number of entries, after which the least recently used
+// entry is removed. The cache is cleared if a setting in the Condition is
+// changed, with the same caveats as Condition.Generation.
+//
+// A TruncateCache is safe for concurrent use.
+type TruncateCache struct {
+	c    *Condition
+	size int
+
+	mu      sync.Mutex
+	profile Profile // Settings the entries were created with.
+	gen     uint32
+	order   *list.List // Most recently used first.
+	entries map[truncateKey]*list.Element
+}
+
+type truncateKey struct {
+	s, tail string
+	w       int
+}
+
+type truncateEntry struct {
+	key    truncateKey
+	result string
+}
+
+// NewTruncateCache creates a new cache for Truncate with c, which holds up to
+// size entries. The size is set to 1024 if it's 0 or lower.
+func NewTruncateCache(c *Condition, size int) *TruncateCache {
+	if size <= 0 {
+		size = 1024
+	}
+	return &TruncateCache{
+		c:       c,
+		size:    size,
+		profile: c.Profile(),
+		gen:     c.gen,
+		order:   list.New(),
+		entries: make(map[truncateKey]*list.Element, size),
+	}
+}
+
+// Truncate s to at most w cells, appending tail if s was truncated; see
+// Condition.Truncate.
+func (t *TruncateCache) Truncate(s string, w int, tail string) string {
+	k := truncateKey{s, tail, w}
+	p := t.c.Profile()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if p != t.profile || t.c.gen != t.gen {
+		t.reset()
+		t.profile, t.gen = p, t.c.gen
+	}
+	if e, ok := t.entries[k]; ok {
+		t.order.MoveToFront(e)
+		return e.Value.(*truncateEntry).result
+	}
+
+	r := t.c.Truncate(s, w, tail)
+	if t.order.Len() >= t.size {
+		old := t.order.Back()
+		t.order.Remove(old)
+		delete(t.entries, old.Value.(*truncateEntry).key)
+	}
+	t.entries[k] = t.order.PushFront(&truncateEntry{k, r})
+	return r
+}
+
+// Len returns the number of entries in the cache.
+func (t *TruncateCache) Len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.order.Len()
+}
+
+// Reset removes all entries from the cache.
+func (t *TruncateCache) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.reset()
+}
+
+func (t *TruncateCache) reset() {
+	t.order.Init()
+	t.entries = make(map[truncateKey]*list.Element, t.size)
+}
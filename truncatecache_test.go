@@ -0,0 +1,79 @@
+package runewidth
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestTruncateCache(t *testing.T) {
+	c := newCond(false)
+	tc := NewTruncateCache(c, 2)
+
+	tests := []struct {
+		in   string
+		w    int
+		tail string
+		want string
+		l    int
+	}{
+		{"☆abcdef", 4, "…", "☆ab…", 1},
+		{"☆abcdef", 4, "…", "☆ab…", 1},
+		{"☆abcdef", 4, "", "☆abc", 2},
+		{"☆abcdef", 3, "", "☆ab", 2},
+		{"☆abcdef", 4, "…", "☆ab…", 2},
+	}
+	for _, tt := range tests {
+		if have := tc.Truncate(tt.in, tt.w, tt.tail); have != tt.want {
+			t.Errorf("Truncate(%q, %d, %q) = %q, want %q", tt.in, tt.w, tt.tail, have, tt.want)
+		}
+		if have := tc.Len(); have != tt.l {
+			t.Errorf("Len() = %d, want %d", have, tt.l)
+		}
+	}
+
+	// Most recently used is kept.
+	k := truncateKey{"☆abcdef", "…", 4}
+	if _, ok := tc.entries[k]; !ok {
+		t.Errorf("most recently used entry removed")
+	}
+
+	// Changing the Condition clears the cache.
+	c.EastAsianWidth = true
+	if have := tc.Truncate("☆abcdef", 4, ""); have != "☆ab" {
+		t.Errorf("after EastAsianWidth: %q", have)
+	}
+	if have := tc.Len(); have != 1 {
+		t.Errorf("Len() = %d, want 1", have)
+	}
+	c.SetWidth('a', 2)
+	if have := tc.Truncate("☆abcdef", 4, ""); have != "☆a" {
+		t.Errorf("after SetWidth: %q", have)
+	}
+
+	tc.Reset()
+	if have := tc.Len(); have != 0 {
+		t.Errorf("Len() = %d after Reset", have)
+	}
+	if NewTruncateCache(c, 0).size != 1024 {
+		t.Error("default size not set")
+	}
+}
+
+func TestTruncateCacheConcurrent(t *testing.T) {
+	tc := NewTruncateCache(newCond(false), 16)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				s := strconv.Itoa(i*j) + "世界"
+				if have, want := tc.Truncate(s, 3, ""), Truncate(s, 3, ""); have != want {
+					t.Errorf("%q: have %q, want %q", s, have, want)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,55 @@
+package runewidth
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// isVariationSelector reports if r is one of the variation selectors VS1 to
+// VS256.
+func isVariationSelector(r rune) bool {
+	return (r >= 0xFE00 && r <= 0xFE0F) || (r >= 0xE0100 && r <= 0xE01EF)
+}
+
+// hasVariation reports if r can have a text and emoji presentation selected
+// with VS15 and VS16. The emoji table doesn't include the keycap bases and the
+// copyright and registered signs, as they're below U+00FF.
+func hasVariation(r rune) bool {
+	if r == '#' || r == '*' || (r >= '0' && r <= '9') || r == 0xA9 || r == 0xAE {
+		return true
+	}
+	return inTable(r, emoji)
+}
+
+// StripVariationSelectors removes all variation selectors (U+FE00 to U+FE0F
+// and U+E0100 to U+E01EF) from s, and returns the number of removed
+// selectors.
+//
+// Terminals differ in how they display sequences with U+FE0F VARIATION
+// SELECTOR-16 (emoji presentation); removing them gives more predictable
+// results.
+func StripVariationSelectors(s string) (string, int) {
+	var (
+		b *strings.Builder
+		n int
+	)
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case isVariationSelector(r) && b == nil:
+			b = new(strings.Builder)
+			b.Grow(len(s))
+			b.WriteString(s[:i])
+			n++
+		case isVariationSelector(r):
+			n++
+		case b != nil:
+			b.WriteString(s[i : i+size])
+		}
+		i += size
+	}
+	if b == nil {
+		return s, 0
+	}
+	return b.String(), n
+}
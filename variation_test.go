@@ -0,0 +1,59 @@
+package runewidth
+
+import (
+	"testing"
+)
+
+func TestStripVariationSelectors(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		n    int
+	}{
+		{"", "", 0},
+		{"abc", "abc", 0},
+		{"❤️", "❤", 1},
+		{"a︎b️c", "abc", 2},
+		{"葛\U000e0100飾", "葛飾", 1},
+		{"️️", "", 2},
+	}
+
+	for _, tt := range tests {
+		have, n := StripVariationSelectors(tt.in)
+		if have != tt.want || n != tt.n {
+			t.Errorf("StripVariationSelectors(%q) = (%q, %d), want (%q, %d)", tt.in, have, n, tt.want, tt.n)
+		}
+	}
+}
+
+func TestVariationSelectors(t *testing.T) {
+	tests := []struct {
+		in       string
+		off, on  int
+		truncate string
+	}{
+		{"❤️", 2, 2, "❤️"},
+		{"❤︎", 2, 1, "❤︎"},
+		{"⌚︎", 3, 1, "⌚︎"},
+		{"⌚️", 3, 2, "⌚️"},
+		{"#️⃣", 2, 2, "#️⃣"},
+		{"a️", 2, 1, "a️"},
+		{"©️", 2, 2, "©️"},
+		{"️", 1, 0, "️"},
+		{"❤️❤️❤️", 6, 6, "❤️"},
+	}
+
+	for _, tt := range tests {
+		c := newCond(false)
+		if have := c.StringWidth(tt.in); have != tt.off {
+			t.Errorf("StringWidth(%q) = %d, want %d", tt.in, have, tt.off)
+		}
+		c.VariationSelectors = true
+		if have := c.StringWidth(tt.in); have != tt.on {
+			t.Errorf("StringWidth(%q) = %d, want %d (VariationSelectors)", tt.in, have, tt.on)
+		}
+		if have := c.Truncate(tt.in, 2, ""); have != tt.truncate {
+			t.Errorf("Truncate(%q) = %q, want %q (VariationSelectors)", tt.in, have, tt.truncate)
+		}
+	}
+}
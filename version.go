@@ -0,0 +1,44 @@
+package runewidth
+
+// unicodeVersion is the Unicode version of the tables in runewidth_table.go.
+// This needs to be updated when regenerating the tables.
+const unicodeVersion = "15.1.0"
+
+// Tables with the wide characters for Condition.UnicodeVersion.
+var doublewidthVersions = map[string]table{
+	"9.0.0":        doublewidth9,
+	"13.0.0":       doublewidth13,
+	unicodeVersion: doublewidth,
+	"16.0.0":       doublewidth16,
+}
+
+// UnicodeVersions returns the Unicode versions that can be used for
+// Condition.UnicodeVersion, from oldest to newest.
+func UnicodeVersions() []string {
+	return []string{"9.0.0", "13.0.0", unicodeVersion, "16.0.0"}
+}
+
+// UnicodeVersion returns the Unicode version of the tables that
+// DefaultCondition uses.
+func UnicodeVersion() string {
+	return DefaultCondition.unicodeVersion()
+}
+
+// unicodeVersion returns the Unicode version of the tables that c uses.
+func (c *Condition) unicodeVersion() string {
+	if _, ok := doublewidthVersions[c.UnicodeVersion]; ok {
+		return c.UnicodeVersion
+	}
+	return unicodeVersion
+}
+
+// doublewidth returns the table with wide characters for the UnicodeVersion.
+func (c *Condition) doublewidth() table {
+	if c.UnicodeVersion == "" {
+		return doublewidth
+	}
+	if t, ok := doublewidthVersions[c.UnicodeVersion]; ok {
+		return t
+	}
+	return doublewidth
+}
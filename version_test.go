@@ -0,0 +1,64 @@
+package runewidth
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestUnicodeVersion(t *testing.T) {
+	tests := []struct {
+		in                rune
+		v9, v13, v15, v16 int
+	}{
+		{'世', 2, 2, 2, 2},
+		{'a', 1, 1, 1, 1},
+		{0x1F93B, 2, 1, 1, 1},
+		{0x1FAD6, 1, 2, 2, 2},
+		{0x1FAF8, 1, 1, 2, 2},
+		{0x1FAE9, 1, 1, 1, 2},
+		{0x2630, 1, 1, 1, 2},
+	}
+
+	for _, tt := range tests {
+		for i, v := range UnicodeVersions() {
+			want := []int{tt.v9, tt.v13, tt.v15, tt.v16}[i]
+			c := newCond(false)
+			c.UnicodeVersion = v
+			if have := c.RuneWidth(tt.in); have != want {
+				t.Errorf("RuneWidth(%U) with %s = %d, want %d", tt.in, v, have, want)
+			}
+		}
+	}
+
+	if have := UnicodeVersion(); have != "15.1.0" {
+		t.Errorf("UnicodeVersion() = %q", have)
+	}
+	c := newCond(false)
+	c.UnicodeVersion = "1.0"
+	if have := c.unicodeVersion(); have != "15.1.0" {
+		t.Errorf("unicodeVersion() = %q", have)
+	}
+	if len(UnicodeVersions()) != len(doublewidthVersions) {
+		t.Errorf("UnicodeVersions() out of sync")
+	}
+}
+
+func TestUnicodeVersionLUT(t *testing.T) {
+	for _, v := range UnicodeVersions() {
+		for _, ea := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%s/%t", v, ea), func(t *testing.T) {
+				c := newCond(ea)
+				c.UnicodeVersion = v
+				lut := newCond(ea)
+				lut.UnicodeVersion = v
+				lut.CreateLUT()
+
+				for r := rune(0); r < 0x40000; r++ {
+					if have, want := lut.RuneWidth(r), c.RuneWidth(r); have != want {
+						t.Fatalf("%U: LUT has %d, want %d", r, have, want)
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package runewidth
+
+import "fmt"
+
+// Class is the width class of a rune; see WidthClass.
+type Class uint8
+
+// Width classes. Most of these are from the East Asian Width property.
+const (
+	ClassNeutral   Class = iota // Not used in East Asian text: 1 cell.
+	ClassNarrow                 // Narrow, such as ASCII: 1 cell.
+	ClassWide                   // Wide, such as CJK ideographs: 2 cells.
+	ClassAmbiguous              // 1 or 2 cells, depending on EastAsianWidth.
+	ClassHalfwidth              // Halfwidth forms, such as "ｱ": 1 cell.
+	ClassFullwidth              // Fullwidth forms, such as "Ａ": 2 cells.
+	ClassCombining              // Combining marks: 0 cells.
+	ClassNonPrint               // Control and format characters: 0 cells.
+	ClassEmoji                  // Emoji and other pictographs: 1 or 2 cells.
+)
+
+func (c Class) String() string {
+	switch c {
+	case ClassNeutral:
+		return "neutral"
+	case ClassNarrow:
+		return "narrow"
+	case ClassWide:
+		return "wide"
+	case ClassAmbiguous:
+		return "ambiguous"
+	case ClassHalfwidth:
+		return "halfwidth"
+	case ClassFullwidth:
+		return "fullwidth"
+	case ClassCombining:
+		return "combining"
+	case ClassNonPrint:
+		return "nonprint"
+	case ClassEmoji:
+		return "emoji"
+	}
+	return fmt.Sprintf("Class(%d)", uint8(c))
+}
+
+// WidthClass returns the width class of r, for code that needs to know why a
+// rune has the width it has rather than just the width.
+//
+// Emoji are ClassEmoji regardless of their width. Wide characters are from
+// the tables for the UnicodeVersion; other settings on the Condition don't
+// change the class, only the width. Invalid code points are ClassNonPrint.
+func (c *Condition) WidthClass(r rune) Class {
+	switch {
+	case r < 0 || r > 0x10FFFF, inTable(r, nonprint):
+		return ClassNonPrint
+	case inTable(r, combining):
+		return ClassCombining
+	case inTable(r, emoji):
+		return ClassEmoji
+	case inTable(r, fullwidth):
+		return ClassFullwidth
+	case inTable(r, halfwidth):
+		return ClassHalfwidth
+	case inTable(r, c.doublewidth()):
+		return ClassWide
+	case inTable(r, ambiguous):
+		return ClassAmbiguous
+	case inTable(r, narrow):
+		return ClassNarrow
+	}
+	return ClassNeutral
+}
+
+// WidthClass returns the width class of r.
+func WidthClass(r rune) Class {
+	return DefaultCondition.WidthClass(r)
+}
@@ -0,0 +1,65 @@
+package runewidth
+
+import "testing"
+
+func TestWidthClass(t *testing.T) {
+	tests := []struct {
+		in   rune
+		want Class
+	}{
+		{'a', ClassNarrow},
+		{'é', ClassAmbiguous},
+		{'ä', ClassNeutral},
+		{'世', ClassWide},
+		{'ｱ', ClassHalfwidth},
+		{'₩', ClassHalfwidth},
+		{'Ａ', ClassFullwidth},
+		{'\u3000', ClassFullwidth},
+		{'\u0301', ClassCombining},
+		{'\u200b', ClassNonPrint},
+		{'\x00', ClassNonPrint},
+		{-1, ClassNonPrint},
+		{0x110000, ClassNonPrint},
+		{'😀', ClassEmoji},
+		{'☺', ClassEmoji},
+		{0xE000, ClassAmbiguous},
+		{'\U0001f93b', ClassNeutral},
+	}
+
+	for _, tt := range tests {
+		if have := WidthClass(tt.in); have != tt.want {
+			t.Errorf("WidthClass(%U) = %s, want %s", tt.in, have, tt.want)
+		}
+	}
+
+	c := newCond(false)
+	c.UnicodeVersion = "9.0.0"
+	if have := c.WidthClass('\U0001f93b'); have != ClassWide {
+		t.Errorf("WidthClass with Unicode 9 = %s", have)
+	}
+	if have := Class(42).String(); have != "Class(42)" {
+		t.Errorf("String() = %q", have)
+	}
+}
+
+// Every class has the width it says it has in the default Condition.
+func TestWidthClassWidth(t *testing.T) {
+	c := newCond(false)
+	for r := rune(0); r <= 0x10FFFF; r++ {
+		w := c.RuneWidth(r)
+		switch cl := c.WidthClass(r); cl {
+		case ClassNarrow, ClassHalfwidth, ClassAmbiguous:
+			if w != 1 {
+				t.Fatalf("%U: %s with width %d", r, cl, w)
+			}
+		case ClassWide, ClassFullwidth:
+			if w != 2 {
+				t.Fatalf("%U: %s with width %d", r, cl, w)
+			}
+		case ClassCombining, ClassNonPrint:
+			if w != 0 {
+				t.Fatalf("%U: %s with width %d", r, cl, w)
+			}
+		}
+	}
+}
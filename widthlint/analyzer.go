@@ -0,0 +1,18 @@
+package widthlint
+
+import "golang.org/x/tools/go/analysis"
+
+// Analyzer reports the places where strings are padded or truncated by their
+// length instead of the display width; see Check.
+var Analyzer = &analysis.Analyzer{
+	Name: "widthlint",
+	Doc:  "report padding and truncating strings by length instead of display width",
+	Run:  run,
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	for _, d := range Check(pass.Files, pass.TypesInfo) {
+		pass.Reportf(d.Pos, "%s", d.Message)
+	}
+	return nil, nil
+}
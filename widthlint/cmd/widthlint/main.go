@@ -0,0 +1,10 @@
+// Command widthlint reports padding and truncating strings by their length
+// instead of the display width.
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/singlechecker"
+	"zgo.at/runewidth/widthlint"
+)
+
+func main() { singlechecker.Main(widthlint.Analyzer) }
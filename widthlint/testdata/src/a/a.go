@@ -0,0 +1,32 @@
+package a
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+func pad(s string) string {
+	return s + strings.Repeat(" ", 20-len(s)) // want "padding with len\\(\\)"
+}
+
+func truncate(s string) {
+	if utf8.RuneCountInString(s) > 20 {
+		s = string([]rune(s)[:20]) // want "truncating with utf8.RuneCountInString\\(\\)"
+	}
+	fmt.Println(s)
+}
+
+func key(s string) string {
+	if len(s) > 250 {
+		s = s[:250]
+	}
+	return s
+}
+
+func bytes(b []byte) []byte {
+	if len(b) > 20 {
+		b = b[:20]
+	}
+	return b
+}
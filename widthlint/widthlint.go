@@ -0,0 +1,278 @@
+// Package widthlint finds code that pads or truncates strings by their length
+// in bytes or runes, which is wrong for display in a terminal if the strings
+// can contain wide characters, combining marks, or emoji.
+//
+// Analyzer can be used with the golang.org/x/tools/go/analysis drivers, or run
+// with cmd/widthlint:
+//
+//	go run zgo.at/runewidth/widthlint/cmd/widthlint ./...
+package widthlint
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path"
+	"strings"
+)
+
+// Diagnostic is a problem found by Check.
+type Diagnostic struct {
+	Pos     token.Pos
+	Message string
+}
+
+// Check reports the places in files where strings are padded or truncated
+// using len(s), len([]rune(s)), or utf8.RuneCountInString(s):
+//
+//	s += strings.Repeat(" ", 20-len(s))
+//
+//	if utf8.RuneCountInString(s) > 20 {
+//		s = string([]rune(s)[:20]) + "…"
+//	}
+//	fmt.Println(s)
+//
+// Truncating is only reported if it looks like the string is displayed: if
+// "…" or "..." is appended, or if it's printed with fmt.Print, fmt.Fprint, or
+// io.WriteString (or their variants) in the same function. Many strings are
+// truncated to limit their length in bytes, for example for buffers, keys, or
+// protocol fields, which is correct.
+//
+// The type information in info is used to skip len() on things other than
+// strings; if info is nil or doesn't have the type of an expression it's
+// assumed to be a string.
+func Check(files []*ast.File, info *types.Info) []Diagnostic {
+	var diags []Diagnostic
+	for _, f := range files {
+		var stack []ast.Node // Parents of the current node.
+		ast.Inspect(f, func(n ast.Node) bool {
+			if n == nil {
+				stack = stack[:len(stack)-1]
+				return true
+			}
+			stack = append(stack, n)
+			switch n := n.(type) {
+			case *ast.CallExpr:
+				if isFunc(info, n.Fun, "strings", "Repeat") && len(n.Args) == 2 {
+					if m := findLength(info, n.Args[1]); m != "" {
+						diags = append(diags, Diagnostic{n.Pos(),
+							"padding with " + m + " instead of the display width; use runewidth.FillLeft, FillRight, or StringWidth"})
+					}
+				}
+			case *ast.IfStmt:
+				if d, ok := checkTruncate(info, n, funcBody(stack)); ok {
+					diags = append(diags, d)
+				}
+			}
+			return true
+		})
+	}
+	return diags
+}
+
+// funcBody returns the body of the innermost function in stack.
+func funcBody(stack []ast.Node) *ast.BlockStmt {
+	for i := len(stack) - 1; i >= 0; i-- {
+		switch f := stack[i].(type) {
+		case *ast.FuncDecl:
+			return f.Body
+		case *ast.FuncLit:
+			return f.Body
+		}
+	}
+	return nil
+}
+
+// checkTruncate checks if an if statement compares the length of a string and
+// slices it in the body, and if that string is displayed in the function body.
+func checkTruncate(info *types.Info, n *ast.IfStmt, body *ast.BlockStmt) (Diagnostic, bool) {
+	cmp, ok := n.Cond.(*ast.BinaryExpr)
+	if !ok {
+		return Diagnostic{}, false
+	}
+	switch cmp.Op {
+	case token.GTR, token.GEQ, token.LSS, token.LEQ:
+	default:
+		return Diagnostic{}, false
+	}
+	s, m := length(info, cmp.X)
+	if s == nil {
+		s, m = length(info, cmp.Y)
+	}
+	if s == nil {
+		return Diagnostic{}, false
+	}
+
+	name := types.ExprString(s)
+	var d Diagnostic
+	ast.Inspect(n.Body, func(n ast.Node) bool {
+		if d.Pos.IsValid() {
+			return false
+		}
+		sl, ok := n.(*ast.SliceExpr)
+		if !ok || sl.High == nil {
+			return true
+		}
+		x := sl.X
+		if c, ok := x.(*ast.CallExpr); ok && len(c.Args) == 1 && isRuneSlice(c.Fun) {
+			x = c.Args[0]
+		}
+		if types.ExprString(x) == name {
+			d = Diagnostic{sl.Pos(),
+				"truncating with " + m + " instead of the display width; use runewidth.Truncate"}
+		}
+		return true
+	})
+	if !d.Pos.IsValid() || !(hasEllipsis(n.Body) || printed(info, body, name)) {
+		return Diagnostic{}, false
+	}
+	return d, true
+}
+
+// hasEllipsis reports if there's a string literal with "…" or "..." in n.
+func hasEllipsis(n ast.Node) bool {
+	found := false
+	ast.Inspect(n, func(n ast.Node) bool {
+		if l, ok := n.(*ast.BasicLit); ok && l.Kind == token.STRING &&
+			(strings.Contains(l.Value, "…") || strings.Contains(l.Value, "...")) {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// printFuncs are the functions that write strings for display.
+var printFuncs = []struct{ pkg, name string }{
+	{"fmt", "Print"}, {"fmt", "Printf"}, {"fmt", "Println"},
+	{"fmt", "Fprint"}, {"fmt", "Fprintf"}, {"fmt", "Fprintln"},
+	{"io", "WriteString"},
+}
+
+// printed reports if the expression name is used in the arguments to one of
+// the printFuncs in body.
+func printed(info *types.Info, body *ast.BlockStmt, name string) bool {
+	if body == nil {
+		return false
+	}
+	found := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || found {
+			return !found
+		}
+		for _, f := range printFuncs {
+			if !isFunc(info, call.Fun, f.pkg, f.name) {
+				continue
+			}
+			for _, a := range call.Args {
+				ast.Inspect(a, func(n ast.Node) bool {
+					if e, ok := n.(ast.Expr); ok && types.ExprString(e) == name {
+						found = true
+					}
+					return !found
+				})
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// findLength finds the first length of a string in e, returning a description
+// of how the length is determined.
+func findLength(info *types.Info, e ast.Expr) string {
+	var m string
+	ast.Inspect(e, func(n ast.Node) bool {
+		if e, ok := n.(ast.Expr); ok && m == "" {
+			_, m = length(info, e)
+		}
+		return m == ""
+	})
+	return m
+}
+
+// length returns the string if e gets the length of a string, and a
+// description of how the length is determined.
+func length(info *types.Info, e ast.Expr) (ast.Expr, string) {
+	for {
+		p, ok := e.(*ast.ParenExpr)
+		if !ok {
+			break
+		}
+		e = p.X
+	}
+	call, ok := e.(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 {
+		return nil, ""
+	}
+	arg := call.Args[0]
+	if isFunc(info, call.Fun, "unicode/utf8", "RuneCountInString") {
+		return arg, "utf8.RuneCountInString()"
+	}
+	if id, ok := call.Fun.(*ast.Ident); !ok || id.Name != "len" || !isBuiltin(info, id) {
+		return nil, ""
+	}
+	if c, ok := arg.(*ast.CallExpr); ok && len(c.Args) == 1 && isRuneSlice(c.Fun) {
+		return c.Args[0], "len([]rune())"
+	}
+	if !isString(info, arg) {
+		return nil, ""
+	}
+	return arg, "len()"
+}
+
+// isFunc checks if e refers to the function name in the package with the
+// import path pkg.
+func isFunc(info *types.Info, e ast.Expr, pkg, name string) bool {
+	sel, ok := e.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != name {
+		return false
+	}
+	id, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	if info != nil {
+		if p, ok := info.Uses[id].(*types.PkgName); ok {
+			return p.Imported().Path() == pkg
+		}
+	}
+	return id.Name == path.Base(pkg)
+}
+
+// isBuiltin checks if id refers to a builtin function, rather than something
+// that shadows it.
+func isBuiltin(info *types.Info, id *ast.Ident) bool {
+	if info == nil {
+		return true
+	}
+	obj, ok := info.Uses[id]
+	if !ok {
+		return true
+	}
+	_, ok = obj.(*types.Builtin)
+	return ok
+}
+
+func isString(info *types.Info, e ast.Expr) bool {
+	if info == nil {
+		return true
+	}
+	t := info.TypeOf(e)
+	if t == nil {
+		return true
+	}
+	b, ok := t.Underlying().(*types.Basic)
+	return ok && b.Info()&types.IsString != 0
+}
+
+// isRuneSlice checks if e is the type []rune.
+func isRuneSlice(e ast.Expr) bool {
+	at, ok := e.(*ast.ArrayType)
+	if !ok || at.Len != nil {
+		return false
+	}
+	id, ok := at.Elt.(*ast.Ident)
+	return ok && (id.Name == "rune" || id.Name == "int32")
+}
@@ -0,0 +1,99 @@
+package widthlint
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		in        string
+		want      []string
+		typesOnly bool // Only correct with type information.
+	}{
+		{`s += strings.Repeat(" ", 20-len(s))`,
+			[]string{"padding with len()"}, false},
+		{`s = strings.Repeat(" ", 20-utf8.RuneCountInString(s)) + s`,
+			[]string{"padding with utf8.RuneCountInString()"}, false},
+		{`s += strings.Repeat("-", (20-len([]rune(s)))/2)`,
+			[]string{"padding with len([]rune())"}, false},
+		{`if len(s) > 20 { s = s[:20] }; fmt.Println("name:", s)`,
+			[]string{"truncating with len()"}, false},
+		{`if len(s) > 20 { s = s[:20] }; fmt.Fprintf(os.Stdout, "%s\n", s)`,
+			[]string{"truncating with len()"}, false},
+		{`if len(s) > 20 { s = s[:20] }; io.WriteString(os.Stdout, s+"\n")`,
+			[]string{"truncating with len()"}, false},
+		{`if len(s) > 20 { s = s[:17] + "..." }`,
+			[]string{"truncating with len()"}, false},
+		{`if 20 < utf8.RuneCountInString(s) { s = string([]rune(s)[:20]) + "…" }`,
+			[]string{"truncating with utf8.RuneCountInString()"}, false},
+
+		// Not strings, or not padding or truncating.
+		{`s += strings.Repeat(" ", 20-len(b))`, nil, true},
+		{`if len(b) > 20 { b = b[:20] }`, nil, true},
+		{`if len(s) > 20 { b = b[:20] }`, nil, false},
+		{`if len(s) > 20 { s = s[2:] }`, nil, false},
+		{`if len(b) > 20 { b = b[:20] }; fmt.Println(b)`, nil, true},
+
+		// Truncating to a length in bytes, without displaying it.
+		{`if len(s) > 20 { s = s[:20] }`, nil, false},
+		{`if len(s) > 250 { s = s[:250] }; m := map[string]int{s: 1}; _ = m`, nil, false},
+		{`if len(s) > 20 { s = s[:20] }; _ = fmt.Sprintf("key:%s", s)`, nil, false},
+		{`if len(s) > 20 { s = s[:20] }; os.Stdout.Write(b)`, nil, false},
+		{`s = strings.Repeat(" ", 20)`, nil, false},
+		{`s += strings.Repeat(" ", 20-runewidth.StringWidth(s))`, nil, false},
+		{`len := func(string) int { return 1 }; s += strings.Repeat(" ", 20-len(s))`, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			src := `package x
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+var runewidth struct{ StringWidth func(string) int }
+
+func f(s string, b []byte) {
+	` + tt.in + `
+	_, _, _ = s, b, utf8.RuneError
+	_, _, _, _ = strings.Repeat, fmt.Sprint, io.WriteString, os.Stdout
+}`
+			fset := token.NewFileSet()
+			f, err := parser.ParseFile(fset, "x.go", src, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			info := &types.Info{Types: map[ast.Expr]types.TypeAndValue{}, Uses: map[*ast.Ident]types.Object{}}
+			_, err = (&types.Config{Importer: importer.Default()}).Check("x", fset, []*ast.File{f}, info)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, i := range []*types.Info{info, nil} {
+				if i == nil && tt.typesOnly {
+					continue
+				}
+				have := Check([]*ast.File{f}, i)
+				if len(have) != len(tt.want) {
+					t.Fatalf("have %d diagnostics, want %d: %v", len(have), len(tt.want), have)
+				}
+				for j := range have {
+					if !strings.HasPrefix(have[j].Message, tt.want[j]) {
+						t.Errorf("\nhave: %s\nwant: %s", have[j].Message, tt.want[j])
+					}
+				}
+			}
+		})
+	}
+}
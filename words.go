@@ -0,0 +1,146 @@
+package runewidth
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// WordMode sets how WordIter splits text in words.
+type WordMode uint8
+
+const (
+	// WordsSpace splits on white space; a word is every run of non-space
+	// characters, and the white space isn't returned.
+	WordsSpace WordMode = iota
+
+	// WordsUnicode splits on word boundaries, based on a simplified version
+	// of the Unicode word boundary rules (UAX #29). Every part of the text is
+	// returned: words consisting of letters and numbers (including
+	// apostrophes and periods in words such as "can't" and "3.14"), runs of
+	// white space, and every other character on its own.
+	//
+	// CJK ideographs and kana are returned as a word on their own, as these
+	// languages don't use spaces between words.
+	WordsUnicode
+)
+
+// Word is a word returned by WordIter.
+type Word struct {
+	Text   string // Text of the word.
+	Offset int    // Byte offset in the text.
+	Width  int    // Width in cells.
+}
+
+// WordIter iterates over the words in a string, with their widths.
+//
+//	it := runewidth.Words(s, runewidth.WordsUnicode)
+//	for it.Next() {
+//		w := it.Word()
+//		...
+//	}
+type WordIter struct {
+	c    *Condition
+	s    string
+	pos  int
+	mode WordMode
+	word Word
+}
+
+// Words returns an iterator for the words in s.
+func (c *Condition) Words(s string, mode WordMode) *WordIter {
+	return &WordIter{c: c, s: s, mode: mode}
+}
+
+// Word returns the current word.
+func (it *WordIter) Word() Word { return it.word }
+
+// Next advances to the next word, returning false if there are no more words.
+func (it *WordIter) Next() bool {
+	s := it.s
+	if it.mode == WordsSpace {
+		for it.pos < len(s) {
+			r, size := utf8.DecodeRuneInString(s[it.pos:])
+			if !unicode.IsSpace(r) {
+				break
+			}
+			it.pos += size
+		}
+	}
+	if it.pos >= len(s) {
+		return false
+	}
+
+	start, width := it.pos, 0
+	first := classifyWord(s[start:])
+	for it.pos < len(s) {
+		cl := classifyWord(s[it.pos:])
+		if it.pos > start && !it.joins(first, cl) {
+			break
+		}
+		n, w := it.c.cluster(s[it.pos:])
+		width += w
+		it.pos += n
+		if it.mode == WordsUnicode && (first == wcOther || first == wcMid || first == wcIdeo) {
+			break
+		}
+	}
+	it.word = Word{Text: s[start:it.pos], Offset: start, Width: width}
+	return true
+}
+
+// joins reports if the character at it.pos with class cl is part of the
+// current word, which started with a character of class first.
+func (it *WordIter) joins(first, cl wordClass) bool {
+	if it.mode == WordsSpace {
+		return cl != wcSpace
+	}
+	switch {
+	case first == wcSpace || cl == wcSpace:
+		return first == cl
+	case first == wcOther || first == wcMid || first == wcIdeo:
+		return false
+	case cl == wcLetter:
+		return true
+	case cl == wcMid:
+		// Only if it's followed by a letter, as in "can't".
+		n, _ := it.c.cluster(it.s[it.pos:])
+		return it.pos+n < len(it.s) && classifyWord(it.s[it.pos+n:]) == wcLetter
+	}
+	return false
+}
+
+type wordClass uint8
+
+const (
+	wcOther  wordClass = iota
+	wcLetter           // Letters, numbers, marks, and connectors such as "_".
+	wcSpace
+	wcMid  // Apostrophes and periods, which can be inside a word.
+	wcIdeo // CJK ideographs and kana, which are a word on their own.
+)
+
+func classifyWord(s string) wordClass {
+	r, _ := utf8.DecodeRuneInString(s)
+	switch {
+	case unicode.IsSpace(r):
+		return wcSpace
+	case isIdeographic(r):
+		return wcIdeo
+	case r == '\'' || r == '.' || r == 0x2019 || r == ':' || r == ',':
+		return wcMid
+	case unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r) || unicode.Is(unicode.Pc, r):
+		return wcLetter
+	}
+	return wcOther
+}
+
+// isIdeographic reports if r is a CJK ideograph or kana. Lines can be broken
+// before and after these.
+func isIdeographic(r rune) bool {
+	return r >= 0x3040 && (unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) || unicode.Is(unicode.Katakana, r))
+}
+
+// Words returns an iterator for the words in s.
+func Words(s string, mode WordMode) *WordIter {
+	return DefaultCondition.Words(s, mode)
+}
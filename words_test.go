@@ -0,0 +1,45 @@
+package runewidth
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWords(t *testing.T) {
+	tests := []struct {
+		in   string
+		mode WordMode
+		want []Word
+	}{
+		{"", WordsSpace, nil},
+		{"   ", WordsSpace, nil},
+		{" hello  世界 x", WordsSpace, []Word{{"hello", 1, 5}, {"世界", 8, 4}, {"x", 15, 1}}},
+		{"can't stop, won't", WordsSpace, []Word{{"can't", 0, 5}, {"stop,", 6, 5}, {"won't", 12, 5}}},
+
+		{"", WordsUnicode, nil},
+		{"hello, world!", WordsUnicode, []Word{
+			{"hello", 0, 5}, {",", 5, 1}, {" ", 6, 1}, {"world", 7, 5}, {"!", 12, 1}}},
+		{"can't pi=3.14.", WordsUnicode, []Word{
+			{"can't", 0, 5}, {" ", 5, 1}, {"pi", 6, 2}, {"=", 8, 1}, {"3.14", 9, 4}, {".", 13, 1}}},
+		{"snake_case  x", WordsUnicode, []Word{{"snake_case", 0, 10}, {"  ", 10, 2}, {"x", 12, 1}}},
+		{"café́ (ok)", WordsUnicode, []Word{
+			{"café́", 0, 4}, {" ", 7, 1}, {"(", 8, 1}, {"ok", 9, 2}, {")", 11, 1}}},
+		{"日本語です。", WordsUnicode, []Word{
+			{"日", 0, 2}, {"本", 3, 2}, {"語", 6, 2}, {"で", 9, 2}, {"す", 12, 2}, {"。", 15, 2}}},
+		{"Go言語", WordsUnicode, []Word{{"Go", 0, 2}, {"言", 2, 2}, {"語", 5, 2}}},
+		{"Go言語 x", WordsSpace, []Word{{"Go言語", 0, 6}, {"x", 9, 1}}},
+		{"'quoted'", WordsUnicode, []Word{{"'", 0, 1}, {"quoted", 1, 6}, {"'", 7, 1}}},
+	}
+
+	c := newCond(false)
+	for _, tt := range tests {
+		var have []Word
+		it := c.Words(tt.in, tt.mode)
+		for it.Next() {
+			have = append(have, it.Word())
+		}
+		if !reflect.DeepEqual(have, tt.want) {
+			t.Errorf("Words(%q, %d)\nhave: %q\nwant: %q", tt.in, tt.mode, have, tt.want)
+		}
+	}
+}
@@ -0,0 +1,260 @@
+package runewidth
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// WrapOpts are options for WrapWith.
+type WrapOpts struct {
+	// Words breaks lines at white space rather than at any character. The
+	// white space at the break is removed.
+	//
+	// Lines can also be broken before and after CJK ideographs and kana, as
+	// these languages don't use spaces between words.
+	//
+	// Lines are never broken around a no-break space (U+00A0), narrow
+	// no-break space (U+202F), figure space (U+2007), or word joiner
+	// (U+2060), so that text such as "10 000" or "« oui »" is kept together.
+	Words bool
+
+	// BreakLongWords breaks words that are wider than the width. If this is
+	// false they're kept on a line of their own, which will be wider than the
+	// width. This is only used with Words.
+	BreakLongWords bool
+
+	// Kinsoku applies the Japanese line breaking rules ("kinsoku shori") to
+	// the breaks between CJK characters: lines don't start with closing
+	// punctuation such as "。" and "」" or small kana such as "っ", and don't
+	// end with opening punctuation such as "「". This implies Words, and is
+	// always done with UAX14.
+	Kinsoku bool
+
+	// Reflow joins the lines in a paragraph before wrapping them, instead of
+	// keeping the existing line breaks. Paragraphs are separated by empty
+	// lines.
+	Reflow bool
+
+	// UAX14 breaks lines according to a simplified version of the Unicode
+	// line breaking algorithm (UAX #14), rather than only at white space.
+	// This implies Words.
+	//
+	// Lines can be broken between CJK characters and after hyphens, but not
+	// before closing punctuation such as ")" and "。", after opening
+	// punctuation such as "(" and "「", or around a no-break space.
+	UAX14 bool
+}
+
+// breakKind is how a line ended.
+type breakKind uint8
+
+const (
+	breakEnd    breakKind = iota // End of the text.
+	breakHard                    // Existing line break.
+	breakSoft                    // Break at white space or another break opportunity.
+	breakForced                  // Break in the middle of a word.
+)
+
+// wrapLine is a line in the wrapped text: s[start:end] is the text of the line,
+// and s[end:next] is the line break or white space that was removed.
+type wrapLine struct {
+	start, end, next int
+	kind             breakKind
+}
+
+// WrapWith wraps s so that every line is at most w cells wide, with options to
+// break at words.
+//
+// s is returned unchanged if w <= 0.
+func (c *Condition) WrapWith(s string, w int, opts WrapOpts) string {
+	if w <= 0 {
+		return s
+	}
+	if opts.Reflow {
+		s = c.reflow(s)
+	}
+	var b strings.Builder
+	b.Grow(len(s) + len(s)/8)
+	for _, l := range c.wrap(s, w, opts) {
+		b.WriteString(s[l.start:l.end])
+		switch l.kind {
+		case breakHard:
+			b.WriteString(s[l.end:l.next])
+		case breakSoft, breakForced:
+			b.WriteByte('\n')
+		}
+	}
+	return b.String()
+}
+
+// WrapPositions returns the byte offsets in s where Wrap would insert a line
+// break, without building the wrapped string.
+//
+// The existing line breaks in s aren't included. nil is returned if w <= 0.
+func (c *Condition) WrapPositions(s string, w int) []int {
+	return c.WrapPositionsWith(s, w, WrapOpts{})
+}
+
+// WrapPositionsWith is like WrapPositions, but breaks lines like WrapWith.
+//
+// Every position is the start of a new line. WrapWith removes the white space
+// before a position if it broke the line at white space; this white space is
+// the part of s[:pos] that unicode.IsSpace is true for. Reflow is ignored, as
+// it changes s.
+func (c *Condition) WrapPositionsWith(s string, w int, opts WrapOpts) []int {
+	if w <= 0 {
+		return nil
+	}
+	var pos []int
+	for _, l := range c.wrap(s, w, opts) {
+		if l.kind == breakSoft || l.kind == breakForced {
+			pos = append(pos, l.next)
+		}
+	}
+	return pos
+}
+
+// wrap s to w cells.
+func (c *Condition) wrap(s string, w int, opts WrapOpts) []wrapLine {
+	var lines []wrapLine
+	for pos := 0; pos < len(s); {
+		n, brk := c.nextLine(s[pos:])
+		lines = c.wrapLine(lines, s, pos, pos+n, w, opts)
+		if brk > 0 {
+			lines[len(lines)-1].kind = breakHard
+			lines[len(lines)-1].next = pos + n + brk
+		}
+		pos += n + brk
+	}
+	return lines
+}
+
+// wrapLine wraps s[start:end], which doesn't contain any line breaks, and
+// appends the lines to lines. The last line always has breakEnd.
+func (c *Condition) wrapLine(lines []wrapLine, s string, start, end, w int, opts WrapOpts) []wrapLine {
+	var (
+		ls    = start // Start of the current line.
+		width int     // Width of s[ls:i].
+		// Last break opportunity in the current line: the line ends at bEnd
+		// and the next line starts at bNext. pEnd and pNext are the one
+		// before that.
+		bEnd, bNext = -1, -1
+		pEnd, pNext = -1, -1
+		words       = opts.Words || opts.UAX14 || opts.Kinsoku
+		prev        = lbAL // Class of the previous character.
+		prevSpace   bool   // Previous character was a space.
+		prevIdeo    bool   // Previous character was ideographic.
+	)
+	iter := c.clusters()
+	for i := start; i < end; {
+		n, cw := iter.at(s[:end], i, width)
+		if words {
+			r, _ := utf8.DecodeRuneInString(s[i:])
+			cl := lineBreakClass(r)
+			if unicode.IsSpace(r) && cl != lbGL {
+				if bNext != i || bEnd == -1 {
+					pEnd, pNext = bEnd, bNext
+					bEnd = i
+				}
+				bNext, prevSpace = i+n, true
+				width += cw
+				i += n
+				continue
+			}
+			if opts.UAX14 {
+				switch {
+				case prevSpace && (cl == lbCL || cl == lbGL) && bNext == i:
+					bEnd, bNext = pEnd, pNext // No break before closing punctuation, even after a space.
+				case !prevSpace && i > ls && canBreak(prev, cl):
+					pEnd, pNext = bEnd, bNext
+					bEnd, bNext = i, i
+				}
+			} else {
+				ideo := isIdeographic(r) || isCJKPunct(r)
+				if (ideo || prevIdeo) && !prevSpace && i > ls && cl != lbGL && prev != lbGL &&
+					(!opts.Kinsoku || !kinsoku(prev, cl)) {
+					pEnd, pNext = bEnd, bNext
+					bEnd, bNext = i, i
+				}
+				prevIdeo = ideo
+			}
+			// A zero-width word joiner is part of the cluster before it.
+			if last, _ := utf8.DecodeLastRuneInString(s[i : i+n]); lineBreakClass(last) == lbGL {
+				cl = lbGL
+			}
+			prev, prevSpace = cl, false
+		}
+
+		if width+cw > w && width > 0 {
+			switch {
+			case words && bEnd > ls:
+				lines = append(lines, wrapLine{ls, bEnd, bNext, breakSoft})
+				ls, width = bNext, c.StringWidth(s[bNext:i])
+				bEnd, bNext, pEnd, pNext = -1, -1, -1, -1
+				if width+cw > w && width > 0 && opts.BreakLongWords {
+					lines = append(lines, wrapLine{ls, i, i, breakForced})
+					ls, width = i, 0
+				}
+			case !words || opts.BreakLongWords:
+				lines = append(lines, wrapLine{ls, i, i, breakForced})
+				ls, width = i, 0
+				bEnd, bNext, pEnd, pNext = -1, -1, -1, -1
+			}
+			cw = c.tabStop(s[i], width, cw)
+		}
+		width += cw
+		i += n
+	}
+	return append(lines, wrapLine{ls, end, end, breakEnd})
+}
+
+// reflow joins the lines in every paragraph of s with a space.
+func (c *Condition) reflow(s string) string {
+	var (
+		b    strings.Builder
+		para bool // In a paragraph.
+	)
+	b.Grow(len(s))
+	for pos := 0; pos < len(s); {
+		n, brk := c.nextLine(s[pos:])
+		l := s[pos : pos+n]
+		switch {
+		case strings.TrimSpace(l) == "":
+			if para {
+				b.WriteByte('\n')
+			}
+			b.WriteString(l)
+			b.WriteString(s[pos+n : pos+n+brk])
+			para = false
+		case para:
+			b.WriteByte(' ')
+			b.WriteString(strings.TrimSpace(l))
+		default:
+			b.WriteString(strings.TrimRightFunc(l, unicode.IsSpace))
+			para = true
+		}
+		pos += n + brk
+	}
+	if para && strings.HasSuffix(s, "\n") {
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// WrapWith wraps s so that every line is at most w cells wide, with options to
+// break at words.
+func WrapWith(s string, w int, opts WrapOpts) string {
+	return DefaultCondition.WrapWith(s, w, opts)
+}
+
+// WrapPositions returns the byte offsets in s where Wrap would insert a line
+// break, without building the wrapped string.
+func WrapPositions(s string, w int) []int {
+	return DefaultCondition.WrapPositions(s, w)
+}
+
+// WrapPositionsWith is like WrapPositions, but breaks lines like WrapWith.
+func WrapPositionsWith(s string, w int, opts WrapOpts) []int {
+	return DefaultCondition.WrapPositionsWith(s, w, opts)
+}
@@ -0,0 +1,253 @@
+package runewidth
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+func TestWrapWith(t *testing.T) {
+	var (
+		words = WrapOpts{Words: true}
+		long  = WrapOpts{Words: true, BreakLongWords: true}
+	)
+	tests := []struct {
+		in   string
+		w    int
+		opts WrapOpts
+		want string
+	}{
+		{"", 5, words, ""},
+		{"abc", 0, words, "abc"},
+		{"abcdefgh", 3, WrapOpts{}, "abc\ndef\ngh"},
+		{"the quick brown fox", 10, words, "the quick\nbrown fox"},
+		{"the quick brown fox", 9, words, "the quick\nbrown fox"},
+		{"the quick brown fox", 8, words, "the\nquick\nbrown\nfox"},
+		{"the   quick", 5, words, "the\nquick"},
+		{"a verylongword b", 5, words, "a\nverylongword\nb"},
+		{"a verylongword b", 5, long, "a\nveryl\nongwo\nrd b"},
+		{"verylongword", 5, long, "veryl\nongwo\nrd"},
+		{"  indented text", 10, words, "  indented\ntext"},
+		{"one two\nthree four", 9, words, "one two\nthree\nfour"},
+		{"日本語の テキスト", 8, words, "日本語の\nテキスト"},
+		{"日本語のテキスト", 8, words, "日本語の\nテキスト"},
+		{"日本語のテキスト", 7, words, "日本語\nのテキ\nスト"},
+		{"Go言語で書く hello", 6, words, "Go言語\nで書く\nhello"},
+		{"abc日本 verylongword", 5, words, "abc日\n本\nverylongword"},
+		{"abc ", 3, words, "abc "},
+		{"ab  \ncd", 3, words, "ab  \ncd"},
+
+		{"one\ntwo three\n\nfour\nfive\n", 20, WrapOpts{Words: true, Reflow: true}, "one two three\n\nfour five\n"},
+		{"one\ntwo three\n\nfour\nfive", 8, WrapOpts{Words: true, Reflow: true}, "one two\nthree\n\nfour\nfive"},
+		{"  one\n  two\n", 20, WrapOpts{Words: true, Reflow: true}, "  one two\n"},
+	}
+
+	c := newCond(false)
+	for _, tt := range tests {
+		if have := c.WrapWith(tt.in, tt.w, tt.opts); have != tt.want {
+			t.Errorf("WrapWith(%q, %d, %+v)\nhave: %q\nwant: %q", tt.in, tt.w, tt.opts, have, tt.want)
+		}
+	}
+}
+
+func TestWrapWithCompat(t *testing.T) {
+	c := newCond(false)
+	for _, tt := range wraptests {
+		if have := c.WrapWith(tt.in, tt.w, WrapOpts{}); have != tt.out {
+			t.Errorf("WrapWith(%q, %d) = %q, want %q", tt.in, tt.w, have, tt.out)
+		}
+	}
+}
+
+func TestWrapPositions(t *testing.T) {
+	tests := []struct {
+		in   string
+		w    int
+		want []int
+	}{
+		{"", 5, nil},
+		{"abc", 0, nil},
+		{"abc", 3, nil},
+		{"abcdefgh", 3, []int{3, 6}},
+		{"ab\ncdefg", 3, []int{6}},
+		{"日本語", 4, []int{6}},
+		{"日本語", 3, []int{3, 6}},
+	}
+
+	c := newCond(false)
+	for _, tt := range tests {
+		if have := c.WrapPositions(tt.in, tt.w); !reflect.DeepEqual(have, tt.want) {
+			t.Errorf("WrapPositions(%q, %d)\nhave: %v\nwant: %v", tt.in, tt.w, have, tt.want)
+		}
+	}
+
+	// Inserting a newline at every position should be identical to Wrap.
+	for _, tt := range wraptests {
+		var (
+			b    strings.Builder
+			prev int
+		)
+		for _, p := range c.WrapPositions(tt.in, tt.w) {
+			b.WriteString(tt.in[prev:p])
+			b.WriteByte('\n')
+			prev = p
+		}
+		b.WriteString(tt.in[prev:])
+		if have := b.String(); have != tt.out {
+			t.Errorf("WrapPositions(%q, %d)\nhave: %q\nwant: %q", tt.in, tt.w, have, tt.out)
+		}
+	}
+}
+
+func TestWrapPositionsWith(t *testing.T) {
+	tests := []struct {
+		in   string
+		w    int
+		opts WrapOpts
+		want []int
+	}{
+		{"hello world foo", 8, WrapOpts{}, []int{8}},
+		{"hello world foo", 8, WrapOpts{Words: true}, []int{6, 12}},
+		{"hello  world", 8, WrapOpts{Words: true}, []int{7}},
+		{"abcdefghij kl", 4, WrapOpts{Words: true}, []int{11}},
+		{"abcdefghij kl", 4, WrapOpts{Words: true, BreakLongWords: true}, []int{4, 8, 11}},
+		{"foo (bar)", 6, WrapOpts{UAX14: true}, []int{4}},
+	}
+
+	c := newCond(false)
+	for _, tt := range tests {
+		have := c.WrapPositionsWith(tt.in, tt.w, tt.opts)
+		if !reflect.DeepEqual(have, tt.want) {
+			t.Errorf("WrapPositionsWith(%q, %d, %+v)\nhave: %v\nwant: %v", tt.in, tt.w, tt.opts, have, tt.want)
+		}
+
+		// Removing the white space before every position should be
+		// identical to WrapWith.
+		var (
+			b    strings.Builder
+			prev int
+		)
+		for _, p := range have {
+			b.WriteString(strings.TrimRightFunc(tt.in[prev:p], unicode.IsSpace))
+			b.WriteByte('\n')
+			prev = p
+		}
+		b.WriteString(tt.in[prev:])
+		if w := c.WrapWith(tt.in, tt.w, tt.opts); b.String() != w {
+			t.Errorf("WrapPositionsWith(%q, %d, %+v)\nhave: %q\nwant: %q", tt.in, tt.w, tt.opts, b.String(), w)
+		}
+	}
+}
+
+func TestWrapUAX14(t *testing.T) {
+	uax := WrapOpts{UAX14: true}
+	tests := []struct {
+		in   string
+		w    int
+		want string
+	}{
+		{"the quick brown fox", 10, "the quick\nbrown fox"},
+		{"日本語のテキスト", 8, "日本語の\nテキスト"},
+		{"日本語のテキスト", 7, "日本語\nのテキ\nスト"},
+		{"これは「テスト」です。", 12, "これは「テス\nト」です。"},
+		{"テスト」です。", 6, "テス\nト」で\nす。"},
+		{"あいう。えお", 6, "あい\nう。え\nお"},
+		{"あ「いう", 4, "あ\n「い\nう"},
+		{"ちょっと", 4, "ちょっ\nと"},
+		{"well-known fact", 8, "well-\nknown\nfact"},
+		{"page 1-2", 7, "page\n1-2"},
+		{"100 km away", 5, "100 km\naway"},
+		{"(a b)", 4, "(a\nb)"},
+		{"a b )", 4, "a\nb )"},
+		{"hello, world", 6, "hello,\nworld"},
+		{"Go言語で書く", 6, "Go言語\nで書く"},
+	}
+
+	c := newCond(false)
+	for _, tt := range tests {
+		if have := c.WrapWith(tt.in, tt.w, uax); have != tt.want {
+			t.Errorf("WrapWith(%q, %d)\nhave: %q\nwant: %q", tt.in, tt.w, have, tt.want)
+		}
+	}
+}
+
+func TestWrapNoBreakSpace(t *testing.T) {
+	tests := []struct {
+		in   string
+		w    int
+		want string
+	}{
+		{"cost 10\u00a0000 euro", 10, "cost\n10\u00a0000\neuro"},
+		{"dit «\u202foui\u202f»", 7, "dit\n«\u202foui\u202f»"},
+		{"1\u2007234 5", 5, "1\u2007234\n5"},
+		{"ab\u2060cd ef", 4, "ab\u2060cd\nef"},
+		{"日本\u00a0語", 2, "日\n本\u00a0語"},
+		{"日本\u2060語", 2, "日\n本\u2060語"},
+		{"a\u00a0b c\u00a0d", 3, "a\u00a0b\nc\u00a0d"},
+		{"a\u00a0b\u00a0c", 2, "a\u00a0b\u00a0c"},
+	}
+
+	c := newCond(false)
+	for _, opts := range []WrapOpts{{Words: true}, {UAX14: true}} {
+		for _, tt := range tests {
+			if have := c.WrapWith(tt.in, tt.w, opts); have != tt.want {
+				t.Errorf("WrapWith(%q, %d, %+v)\nhave: %q\nwant: %q", tt.in, tt.w, opts, have, tt.want)
+			}
+		}
+	}
+
+	// Still counted at their width.
+	if have := c.StringWidth("10\u00a0000\u202f€"); have != 8 {
+		t.Errorf("StringWidth = %d, want 8", have)
+	}
+}
+
+func TestWrapKinsoku(t *testing.T) {
+	tests := []struct {
+		in         string
+		w          int
+		want, none string
+	}{
+		{"あいう。えお", 6, "あい\nう。え\nお", "あいう\n。えお"},
+		{"これは「テスト」です。", 12, "これは「テス\nト」です。", "これは「テス\nト」です。"},
+		{"これは「テスト」", 8, "これは\n「テス\nト」", "これは「\nテスト」"},
+		{"ちょっと", 4, "ちょっ\nと", "ちょ\nっと"},
+		{"会議、明日", 6, "会議、\n明日", "会議、\n明日"},
+		{"会議、明日", 4, "会\n議、\n明日", "会議\n、明\n日"},
+		{"the quick brown fox", 10, "the quick\nbrown fox", "the quick\nbrown fox"},
+	}
+
+	c := newCond(false)
+	for _, tt := range tests {
+		if have := c.WrapWith(tt.in, tt.w, WrapOpts{Kinsoku: true}); have != tt.want {
+			t.Errorf("WrapWith(%q, %d, Kinsoku)\nhave: %q\nwant: %q", tt.in, tt.w, have, tt.want)
+		}
+		if have := c.WrapWith(tt.in, tt.w, WrapOpts{Words: true}); have != tt.none {
+			t.Errorf("WrapWith(%q, %d, Words)\nhave: %q\nwant: %q", tt.in, tt.w, have, tt.none)
+		}
+	}
+}
+
+func TestLineBreakClass(t *testing.T) {
+	tests := []struct {
+		in   string
+		want lbClass
+	}{
+		{"aZé", lbAL},
+		{"09", lbNU},
+		{"世あア한", lbID},
+		{"(「（【", lbOP},
+		{")」）】。、!?,.", lbCL},
+		{"ぁっャーゝ々", lbNS},
+		{"-", lbHY},
+		{"  ", lbGL},
+	}
+	for _, tt := range tests {
+		for _, r := range tt.in {
+			if have := lineBreakClass(r); have != tt.want {
+				t.Errorf("lineBreakClass(%q) = %d, want %d", r, have, tt.want)
+			}
+		}
+	}
+}
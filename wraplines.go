@@ -0,0 +1,62 @@
+package runewidth
+
+import "strconv"
+
+// LineBreak is how a line from WrapLines ended.
+type LineBreak uint8
+
+// Line breaks; these have the same values as breakKind.
+const (
+	BreakEnd    LineBreak = iota // Last line of the text.
+	BreakHard                    // Line break in the text.
+	BreakSoft                    // Break at white space or another break opportunity.
+	BreakForced                  // Break in the middle of a word.
+)
+
+func (b LineBreak) String() string {
+	switch b {
+	case BreakEnd:
+		return "end"
+	case BreakHard:
+		return "hard"
+	case BreakSoft:
+		return "soft"
+	case BreakForced:
+		return "forced"
+	}
+	return "LineBreak(" + strconv.Itoa(int(b)) + ")"
+}
+
+// Line is a line from WrapLines.
+type Line struct {
+	Text  string    // Text of the line, without the line break.
+	Break LineBreak // How the line ended.
+}
+
+// WrapLines is like WrapWith, but returns every line with how it ended. This
+// can be used to show a continuation marker only on lines that were broken in
+// the middle of a word, or to keep soft breaks when copying text.
+//
+// Without Words every break that's added is a BreakForced. The white space at
+// a BreakSoft isn't included in the Text. A line break at the end of s doesn't
+// start a new line, and the lines are only split at the existing line breaks
+// if w <= 0.
+func (c *Condition) WrapLines(s string, w int, opts WrapOpts) []Line {
+	if w <= 0 {
+		w = int(^uint(0) >> 1)
+	}
+	if opts.Reflow {
+		s = c.reflow(s)
+	}
+	wl := c.wrap(s, w, opts)
+	lines := make([]Line, 0, len(wl))
+	for _, l := range wl {
+		lines = append(lines, Line{Text: s[l.start:l.end], Break: LineBreak(l.kind)})
+	}
+	return lines
+}
+
+// WrapLines is like WrapWith, but returns every line with how it ended.
+func WrapLines(s string, w int, opts WrapOpts) []Line {
+	return DefaultCondition.WrapLines(s, w, opts)
+}
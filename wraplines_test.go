@@ -0,0 +1,59 @@
+package runewidth
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWrapLines(t *testing.T) {
+	var (
+		words = WrapOpts{Words: true}
+		long  = WrapOpts{Words: true, BreakLongWords: true}
+	)
+	tests := []struct {
+		in   string
+		w    int
+		opts WrapOpts
+		want []Line
+	}{
+		{"", 5, words, []Line{}},
+		{"abc", 5, words, []Line{{"abc", BreakEnd}}},
+		{"abc\n", 5, words, []Line{{"abc", BreakHard}}},
+		{"abc\ndef", 0, words, []Line{{"abc", BreakHard}, {"def", BreakEnd}}},
+		{"abcdefgh", 3, WrapOpts{}, []Line{{"abc", BreakForced}, {"def", BreakForced}, {"gh", BreakEnd}}},
+		{"one two\nthree four", 9, words, []Line{
+			{"one two", BreakHard}, {"three", BreakSoft}, {"four", BreakEnd}}},
+		{"a verylongword b", 5, words, []Line{
+			{"a", BreakSoft}, {"verylongword", BreakSoft}, {"b", BreakEnd}}},
+		{"a verylongword b", 5, long, []Line{
+			{"a", BreakSoft}, {"veryl", BreakForced}, {"ongwo", BreakForced}, {"rd b", BreakEnd}}},
+		{"日本語のテキスト", 8, words, []Line{
+			{"日本語の", BreakSoft}, {"テキスト", BreakEnd}}},
+		{"one\ntwo three\n\nfour", 20, WrapOpts{Words: true, Reflow: true}, []Line{
+			{"one two three", BreakHard}, {"", BreakHard}, {"four", BreakEnd}}},
+	}
+
+	c := newCond(false)
+	for _, tt := range tests {
+		t.Run("", func(t *testing.T) {
+			have := c.WrapLines(tt.in, tt.w, tt.opts)
+			if !reflect.DeepEqual(have, tt.want) {
+				t.Errorf("\nhave: %q\nwant: %q", have, tt.want)
+			}
+
+			if tt.w > 0 {
+				var b strings.Builder
+				for _, l := range have {
+					b.WriteString(l.Text)
+					if l.Break != BreakEnd {
+						b.WriteByte('\n')
+					}
+				}
+				if want := c.WrapWith(tt.in, tt.w, tt.opts); b.String() != want {
+					t.Errorf("different from WrapWith\nhave: %q\nwant: %q", b.String(), want)
+				}
+			}
+		})
+	}
+}
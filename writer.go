@@ -0,0 +1,173 @@
+package runewidth
+
+import (
+	"io"
+	"unicode/utf8"
+)
+
+// Writer is an io.Writer that keeps track of the column of the text written to
+// it, and can wrap or truncate lines that are wider than Width.
+//
+// The last characters of an unfinished line are buffered, as they may still be
+// extended by combining marks or a variation selector in the next write. Call
+// Flush after the last write.
+type Writer struct {
+	// Condition to use; DefaultCondition is used if nil.
+	Condition *Condition
+
+	// Width is the maximum width of lines; lines that are wider are broken at
+	// the cell boundary like Wrap does, or truncated with Tail if Truncate is
+	// set. Lines are never changed if this is 0.
+	Width    int
+	Truncate bool
+	Tail     string
+
+	w       io.Writer
+	err     error
+	pend    []byte // Input that's not yet processed.
+	out     []byte // Output for the current write.
+	held    []byte // Characters that are written only if the line isn't truncated.
+	heldW   int
+	col     int
+	lines   int
+	discard bool // Line was truncated; discard until the next line break.
+}
+
+// NewWriter creates a new Writer that writes to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+func (w *Writer) cond() *Condition {
+	if w.Condition == nil {
+		return DefaultCondition
+	}
+	return w.Condition
+}
+
+// Column returns the width of the current line, without the buffered
+// characters; call Flush first to include them.
+func (w *Writer) Column() int { return w.col }
+
+// Lines returns the number of line breaks written, including the ones added
+// when wrapping.
+func (w *Writer) Lines() int { return w.lines }
+
+// Write writes p, wrapping or truncating lines wider than Width.
+func (w *Writer) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	w.pend = append(w.pend, p...)
+	w.process(false)
+	if err := w.write(); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush writes any buffered text.
+//
+// If Truncate is set and Flush is called in the middle of a line, there may be
+// less room for the Tail if the line is truncated after this.
+func (w *Writer) Flush() error {
+	if w.err != nil {
+		return w.err
+	}
+	w.process(true)
+	w.out = append(w.out, w.held...)
+	w.held, w.heldW = w.held[:0], 0
+	return w.write()
+}
+
+func (w *Writer) write() error {
+	if len(w.out) > 0 {
+		_, w.err = w.w.Write(w.out)
+		w.out = w.out[:0]
+	}
+	return w.err
+}
+
+// process the pending input. The characters at the end that may still be
+// extended are kept in pend unless final is set.
+func (w *Writer) process(final bool) {
+	var (
+		c    = w.cond()
+		s    = bytesToString(w.pend)
+		i    int
+		iter = c.clusters()
+	)
+	for i < len(s) {
+		if !final && !utf8.FullRuneInString(s[i:]) {
+			break
+		}
+		if brk := c.lineBreak(s[i:]); brk > 0 {
+			if !final && s[i] == '\r' && i+brk == len(s) {
+				break // May be followed by "\n".
+			}
+			w.endLine(s[i : i+brk])
+			w.lines++
+			i += brk
+			continue
+		}
+		if s[i] == '\r' && c.Newlines&NewlineCRReset != 0 {
+			w.endLine(s[i : i+1])
+			i++
+			continue
+		}
+		n, cw := iter.at(s, i, w.col)
+		if !final && !clusterEnds(s[i+n:]) {
+			break
+		}
+		w.add(c, s[i:i+n], cw)
+		i += n
+	}
+	w.pend = append(w.pend[:0], s[i:]...)
+}
+
+// endLine writes the held characters and the line break brk.
+func (w *Writer) endLine(brk string) {
+	w.out = append(w.out, w.held...)
+	w.out = append(w.out, brk...)
+	w.held, w.heldW, w.col, w.discard = w.held[:0], 0, 0, false
+}
+
+// add the character cl that's cw cells wide.
+func (w *Writer) add(c *Condition, cl string, cw int) {
+	if w.discard {
+		return
+	}
+	if w.Width <= 0 || (!w.Truncate && w.col+cw <= w.Width) {
+		w.out = append(w.out, cl...)
+		w.col += cw
+		return
+	}
+	if !w.Truncate {
+		if w.col > 0 {
+			w.out = append(w.out, '\n')
+			w.col, cw = 0, c.tabStop(cl[0], 0, cw)
+			w.lines++
+		}
+		w.out = append(w.out, cl...)
+		w.col += cw
+		return
+	}
+
+	// Characters that fit only if there's no need for the tail are held until
+	// the end of the line.
+	switch {
+	case len(w.held) == 0 && w.col+cw <= w.Width-c.StringWidth(w.Tail):
+		w.out = append(w.out, cl...)
+		w.col += cw
+	case w.col+cw <= w.Width:
+		w.held = append(w.held, cl...)
+		w.heldW += cw
+		w.col += cw
+	default:
+		w.col -= w.heldW
+		tail := c.Truncate(w.Tail, w.Width-w.col, "")
+		w.out = append(w.out, tail...)
+		w.col += c.StringWidth(tail)
+		w.held, w.heldW, w.discard = w.held[:0], 0, true
+	}
+}
@@ -0,0 +1,110 @@
+package runewidth
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// writeChunks writes s to w in chunks of n bytes.
+func writeChunks(t *testing.T, w *Writer, s string, n int) {
+	t.Helper()
+	for i := 0; i < len(s); i += n {
+		end := i + n
+		if end > len(s) {
+			end = len(s)
+		}
+		if _, err := w.Write([]byte(s[i:end])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWriter(t *testing.T) {
+	tests := []string{
+		"",
+		"hello, world",
+		"つのだ☆HIRO\nabc\n",
+		"a\u20dd \U0001F469\u200d\U0001F467 \u2764\ufe0f",
+		"a\u0301b\u0301c\u0301d\u0301e\u0301f",
+		"a\tb\tc\n\t\tx",
+		"\xe3\x81\xffabcdefg\xe3",
+		"first line\n\nthird line that's a bit longer",
+	}
+	for _, s := range tests {
+		for _, width := range []int{0, 1, 3, 7} {
+			for _, n := range []int{1, 2, 1000} {
+				t.Run(fmt.Sprintf("%q/%d/%d", s, width, n), func(t *testing.T) {
+					c := newCond(false)
+					c.VariationSelectors, c.WideEnclosing, c.ZWJ = true, true, ZWJEmoji
+
+					b := new(strings.Builder)
+					w := NewWriter(b)
+					w.Condition, w.Width = c, width
+					writeChunks(t, w, s, n)
+					if want := c.Wrap(s, width); b.String() != want {
+						t.Errorf("wrap\nhave: %q\nwant: %q", b.String(), want)
+					}
+					if want := strings.Count(b.String(), "\n"); w.Lines() != want {
+						t.Errorf("Lines = %d, want %d", w.Lines(), want)
+					}
+					lines := strings.Split(b.String(), "\n")
+					if want := c.StringWidth(lines[len(lines)-1]); w.Column() != want {
+						t.Errorf("Column = %d, want %d", w.Column(), want)
+					}
+
+					for _, tail := range []string{"", "…", "[…]"} {
+						b.Reset()
+						w := NewWriter(b)
+						w.Condition, w.Width, w.Truncate, w.Tail = c, width, true, tail
+						writeChunks(t, w, s, n)
+
+						want := s
+						if width > 0 {
+							lines := strings.Split(s, "\n")
+							for i := range lines {
+								lines[i] = c.Truncate(lines[i], width, tail)
+							}
+							want = strings.Join(lines, "\n")
+						}
+						if b.String() != want {
+							t.Errorf("truncate with %q\nhave: %q\nwant: %q", tail, b.String(), want)
+						}
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestWriterSequences(t *testing.T) {
+	c := newCond(false)
+	c.VariationSelectors, c.Newlines = true, NewlineCR
+
+	b := new(strings.Builder)
+	w := NewWriter(b)
+	w.Condition = c
+	for _, s := range []string{"❤", "\ufe0f", "x\r", "\nab", "c\r", "d"} {
+		w.Write([]byte(s))
+	}
+	w.Flush()
+	if w.Column() != 1 || w.Lines() != 2 {
+		t.Errorf("Column = %d, Lines = %d; want 1, 2", w.Column(), w.Lines())
+	}
+	if want := "❤\ufe0fx\r\nabc\rd"; b.String() != want {
+		t.Errorf("\nhave: %q\nwant: %q", b.String(), want)
+	}
+
+	// Width of the VS16 sequence after the second write.
+	w = NewWriter(new(strings.Builder))
+	w.Condition = c
+	w.Write([]byte("❤"))
+	w.Write([]byte("\ufe0f"))
+	w.Flush()
+	if w.Column() != 2 {
+		t.Errorf("Column = %d, want 2", w.Column())
+	}
+}